@@ -0,0 +1,122 @@
+package jsonschema2openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"runtime"
+	"testing"
+)
+
+// syntheticSchema generates JSON Schema with n definitions, each using most features that are translated:
+// nested objects, nullable types, examples, exclusive bounds, tuples, dependencies, conditions and discriminated cases
+func syntheticSchema(n int) map[string]interface{} {
+	definitions := make(map[string]interface{}, n)
+	for i := 0; i < n; i++ {
+		next := fmt.Sprintf("#/definitions/Type%d", (i+1)%n)
+		definitions[fmt.Sprintf("Type%d", i)] = map[string]interface{}{
+			"type":     "object",
+			"$comment": "generated",
+			"required": []interface{}{"id", "name"},
+			"properties": map[string]interface{}{
+				"id":   map[string]interface{}{"type": "integer", "exclusiveMinimum": 0.0},
+				"name": map[string]interface{}{"type": "string", "maxLength": 64.0, "examples": []interface{}{"a", "b"}},
+				"note": map[string]interface{}{"oneOf": []interface{}{
+					map[string]interface{}{"type": "string"},
+					map[string]interface{}{"type": "null"},
+				}},
+				"next": map[string]interface{}{"$ref": next},
+				"tags": map[string]interface{}{
+					"type":  "array",
+					"items": map[string]interface{}{"type": "string", "enum": []interface{}{"x", "y", "z"}},
+				},
+				"point": map[string]interface{}{
+					"items":           []interface{}{map[string]interface{}{"type": "number"}, map[string]interface{}{"type": "number"}},
+					"additionalItems": false,
+				},
+				"kind": map[string]interface{}{"type": "string", "enum": []interface{}{"a", "b"}},
+				"nested": map[string]interface{}{
+					"type": "object",
+					"properties": map[string]interface{}{
+						"created": map[string]interface{}{"type": "string", "format": "date-time"},
+						"data":    map[string]interface{}{"type": "string", "contentEncoding": "base64"},
+						"any":     true,
+					},
+					"additionalProperties": false,
+				},
+			},
+			"dependencies": map[string]interface{}{"note": []interface{}{"name"}},
+			"if":           map[string]interface{}{"properties": map[string]interface{}{"kind": map[string]interface{}{"const": "a"}}},
+			"then":         map[string]interface{}{"required": []interface{}{"tags"}},
+			"else":         map[string]interface{}{"required": []interface{}{"point"}},
+		}
+		if i%10 == 0 {
+			definitions[fmt.Sprintf("Event%d", i)] = map[string]interface{}{
+				"oneOf": []interface{}{syntheticCase("v1", next), syntheticCase("v2", next)},
+			}
+		}
+	}
+	return map[string]interface{}{"definitions": definitions}
+}
+
+func syntheticCase(version, ref string) interface{} {
+	condition := func() map[string]interface{} {
+		return map[string]interface{}{"properties": map[string]interface{}{
+			"version": map[string]interface{}{"enum": []interface{}{version}},
+		}}
+	}
+	return map[string]interface{}{
+		"if":   condition(),
+		"then": map[string]interface{}{"$ref": ref},
+		"else": condition(),
+	}
+}
+
+func benchmarkSizes(b *testing.B, f func(b *testing.B, n int)) {
+	for _, n := range []int{10, 100, 1000} {
+		b.Run(fmt.Sprintf("definitions=%d", n), func(b *testing.B) {
+			f(b, n)
+		})
+	}
+}
+
+func BenchmarkTranslateDefinitions(b *testing.B) {
+	benchmarkSizes(b, func(b *testing.B, n int) {
+		definitions := syntheticSchema(n)["definitions"].(map[string]interface{})
+		b.ReportAllocs()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			TranslateDefinitions(definitions)
+		}
+	})
+}
+
+func BenchmarkTranslateDefinitionsParallel(b *testing.B) {
+	benchmarkSizes(b, func(b *testing.B, n int) {
+		definitions := syntheticSchema(n)["definitions"].(map[string]interface{})
+		opts := Options{Workers: runtime.GOMAXPROCS(0)}
+		b.ReportAllocs()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			if _, err := TranslateDefinitionsWithOptions(definitions, opts); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+func BenchmarkPutSchemaIntoOpenAPI(b *testing.B) {
+	benchmarkSizes(b, func(b *testing.B, n int) {
+		schema, err := json.Marshal(syntheticSchema(n))
+		if err != nil {
+			b.Fatal(err)
+		}
+		b.SetBytes(int64(len(schema)))
+		b.ReportAllocs()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			if _, err := PutSchemaIntoOpenAPI(string(schema), minOpenAPI); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
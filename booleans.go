@@ -0,0 +1,58 @@
+package jsonschema2openapi
+
+// Keywords where OpenAPI 3.0 allows boolean instead of schema
+var booleanSchemaKeywords = map[string]bool{
+	"additionalProperties": true,
+	"additionalItems":      true, // is translated together with tuples
+}
+
+// replaceBooleanSchemas replaces boolean schemas in subschemas of schema with objects:
+// true becomes {} and false becomes {"not": {}}. Booleans are left where OpenAPI allows them.
+func replaceBooleanSchemas(schema map[string]interface{}) map[string]interface{} {
+	for k, v := range schema {
+		if booleanSchemaKeywords[k] {
+			continue
+		}
+		if schemaKeywords[k] {
+			schema[k] = booleanSchema(v)
+		}
+		if elems, ok := v.([]interface{}); ok && schemaArrayKeywords[k] {
+			res := make([]interface{}, len(elems))
+			for i, elem := range elems {
+				res[i] = booleanSchema(elem)
+			}
+			schema[k] = res
+		}
+		if schemas, ok := v.(map[string]interface{}); ok && schemaMapKeywords[k] {
+			schema[k] = booleanSchemaMap(schemas)
+		}
+	}
+	return schema
+}
+
+// booleanSchemaMap returns copy of object like properties or definitions, with boolean schemas replaced
+func booleanSchemaMap(schemas map[string]interface{}) map[string]interface{} {
+	res := make(map[string]interface{})
+	for k, v := range schemas {
+		if _, ok := v.([]interface{}); ok {
+			res[k] = v // list of required properties in dependencies
+		} else {
+			res[k] = booleanSchema(v)
+		}
+	}
+	return res
+}
+
+// booleanSchema returns schema equivalent to boolean one, or jsonData itself when it is not boolean
+func booleanSchema(jsonData interface{}) interface{} {
+	b, ok := jsonData.(bool)
+	if !ok {
+		return jsonData
+	}
+	if b {
+		return map[string]interface{}{}
+	}
+	return map[string]interface{}{
+		"not": map[string]interface{}{},
+	}
+}
@@ -0,0 +1,48 @@
+package jsonschema2openapi
+
+import (
+	"encoding/json"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Boolean schemas", func() {
+	It("should be replaced where OpenAPI requires object", func() {
+		var definitions map[string]interface{}
+		Expect(json.Unmarshal([]byte(`{
+			"Anything": true,
+			"Strict": {
+				"type": "object",
+				"properties": {
+					"x": false,
+					"list": { "type": "array", "items": true, "uniqueItems": true }
+				},
+				"additionalProperties": false,
+				"allOf": [ true, { "not": true } ]
+			}
+		}`), &definitions)).To(Succeed())
+
+		res, err := json.Marshal(TranslateDefinitions(definitions))
+		Expect(err).To(BeNil())
+		Expect(res).To(MatchJSON(`{
+			"Anything": {},
+			"Strict": {
+				"type": "object",
+				"properties": {
+					"x": { "not": {} },
+					"list": { "type": "array", "items": {}, "uniqueItems": true }
+				},
+				"additionalProperties": false,
+				"allOf": [ {}, { "not": {} } ]
+			}
+		}`))
+	})
+
+	It("should let conditions be translated", func() {
+		res := TranslateDefinitions(map[string]interface{}{
+			"Never": map[string]interface{}{"if": true, "then": false, "else": true},
+		})
+		Expect(res).To(HaveKeyWithValue("Never", HaveKey("anyOf")))
+	})
+})
@@ -0,0 +1,160 @@
+// Command jsonschema2openapi translates JSON Schema into OpenAPI spec, and compares translated specs.
+//
+// Usage:
+//
+//	jsonschema2openapi convert -template openapi.json [-include-root] [-validate] [-check-refs] [-prune] [-deduplicate] [-max-input-size N] [-max-depth N] schema.json
+//	jsonschema2openapi diff [-format json|text] [-direction both|request|response] [-max-input-size N] [-max-depth N] old.json new.json
+//
+// diff accepts OpenAPI specs, or JSON Schemas with definitions, which are translated before comparison.
+// It exits with status 1 when there are breaking changes, so could be used for CI gating. Which changes are breaking
+// depends on whether schemas are used in requests, responses or both, which is default.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/bunyk/jsonschema2openapi"
+)
+
+const usage = `Usage:
+  jsonschema2openapi convert -template openapi.json [-include-root] [-validate] [-check-refs] [-prune] [-deduplicate] [-max-input-size N] [-max-depth N] schema.json
+  jsonschema2openapi diff [-format json|text] [-direction both|request|response] [-max-input-size N] [-max-depth N] old.json new.json
+`
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+	var err error
+	switch os.Args[1] {
+	case "convert":
+		err = convert(os.Args[2:])
+	case "diff":
+		var breaking bool
+		breaking, err = diff(os.Args[2:])
+		if err == nil && breaking {
+			os.Exit(1)
+		}
+	default:
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err.Error())
+		os.Exit(2)
+	}
+}
+
+func convert(args []string) error {
+	flags := flag.NewFlagSet("convert", flag.ExitOnError)
+	template := flags.String("template", "", "OpenAPI spec to put schemas into")
+	includeRoot := flags.Bool("include-root", false, "add root schema to components")
+	validate := flags.Bool("validate", false, "validate resulting spec against OpenAPI 3.0 schema")
+	checkRefs := flags.Bool("check-refs", false, "fail when references point at nothing")
+	prune := flags.Bool("prune", false, "remove components which are not used by paths of template")
+	deduplicate := flags.Bool("deduplicate", false, "merge structurally equal components")
+	maxInputSize, maxDepth := limitFlags(flags)
+	flags.Parse(args)
+	if flags.NArg() != 1 || *template == "" {
+		return fmt.Errorf("convert needs -template and one schema file")
+	}
+
+	schema, err := os.Open(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	defer schema.Close()
+	tmpl, err := os.Open(*template)
+	if err != nil {
+		return err
+	}
+	defer tmpl.Close()
+	return jsonschema2openapi.Convert(context.Background(), schema, tmpl, os.Stdout, jsonschema2openapi.Options{
+		IncludeRoot:      *includeRoot,
+		Validate:         *validate,
+		CheckRefs:        *checkRefs,
+		PruneUnreachable: *prune,
+		Deduplicate:      *deduplicate,
+		MaxInputSize:     *maxInputSize,
+		MaxDepth:         *maxDepth,
+	})
+}
+
+// limitFlags adds flags for Options.MaxInputSize and Options.MaxDepth
+func limitFlags(flags *flag.FlagSet) (maxInputSize *int64, maxDepth *int) {
+	maxInputSize = flags.Int64("max-input-size", 0, "reject input files larger than this number of bytes, 0 means no limit")
+	maxDepth = flags.Int("max-depth", 0, "reject input files nested deeper than this number of levels, 0 means no limit")
+	return maxInputSize, maxDepth
+}
+
+// diffReport is output of diff in json format
+type diffReport struct {
+	Breaking bool                        `json:"breaking"`
+	Changes  []jsonschema2openapi.Change `json:"changes"`
+}
+
+func diff(args []string) (bool, error) {
+	flags := flag.NewFlagSet("diff", flag.ExitOnError)
+	format := flags.String("format", "json", "output format: json or text")
+	direction := flags.String("direction", "both", "schemas are used in: both, request or response")
+	maxInputSize, maxDepth := limitFlags(flags)
+	flags.Parse(args)
+	if flags.NArg() != 2 {
+		return false, fmt.Errorf("diff needs old and new files")
+	}
+	if *format != "json" && *format != "text" {
+		return false, fmt.Errorf("unknown format %q", *format)
+	}
+	switch jsonschema2openapi.Direction(*direction) {
+	case jsonschema2openapi.BothDirections, jsonschema2openapi.Requests, jsonschema2openapi.Responses:
+	default:
+		return false, fmt.Errorf("unknown direction %q", *direction)
+	}
+
+	opts := jsonschema2openapi.Options{MaxInputSize: *maxInputSize, MaxDepth: *maxDepth}
+	old, err := readComponents(flags.Arg(0), opts)
+	if err != nil {
+		return false, err
+	}
+	new, err := readComponents(flags.Arg(1), opts)
+	if err != nil {
+		return false, err
+	}
+	report := diffReport{
+		Changes: jsonschema2openapi.DiffWithDirection(old, new, jsonschema2openapi.Direction(*direction)),
+	}
+	if report.Changes == nil {
+		report.Changes = []jsonschema2openapi.Change{}
+	}
+	report.Breaking = jsonschema2openapi.HasBreaking(report.Changes)
+
+	if *format == "text" {
+		for _, c := range report.Changes {
+			fmt.Println(c.String())
+		}
+		return report.Breaking, nil
+	}
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", " ")
+	return report.Breaking, enc.Encode(report)
+}
+
+// readComponents reads components/schemas of OpenAPI spec, or translates definitions of JSON Schema
+func readComponents(filename string, opts jsonschema2openapi.Options) (map[string]interface{}, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	components, err := jsonschema2openapi.ReadComponents(context.Background(), f, opts)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %s", filename, err.Error())
+	}
+	return components, nil
+}
@@ -0,0 +1,134 @@
+package jsonschema2openapi
+
+import (
+	"encoding/json"
+	"sync"
+
+	"github.com/bunyk/jsonschema2openapi/fixtures"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+// Schema which goes through every pass of translation
+const everyPassJSON = `{
+	"definitions": {
+		"map[string]Pet": {
+			"type": "object",
+			"$comment": "generated",
+			"patternProperties": {"^[a-z]+$": {"$ref": "#/definitions/Pet"}},
+			"definitions": {"Nested": {"type": "string", "contentEncoding": "base64"}}
+		},
+		"Pet": {
+			"type": "object",
+			"required": ["name"],
+			"properties": {
+				"name": {"type": "string", "examples": ["Rex", "Fido"]},
+				"age": {"type": "integer", "exclusiveMinimum": 0, "minimum": -1},
+				"owner": {"oneOf": [{"type": "string"}, {"type": "null"}]},
+				"position": {"items": [{"type": "number"}, {"type": "number"}], "additionalItems": false},
+				"anything": true,
+				"kind": {"enum": ["cat", "dog"]}
+			},
+			"dependencies": {"owner": ["name"]},
+			"anyOf": [{"required": ["age"]}, {"required": ["owner"]}],
+			"allOf": [{"required": ["name"]}],
+			"if": {"properties": {"kind": {"const": "cat"}}},
+			"then": {"required": ["age"]},
+			"else": {"required": ["owner"]},
+			"goType": "Pet"
+		}
+	}
+}`
+
+// decodeDefinitions decodes definitions of schema, which are translated concurrently
+func decodeDefinitions(schemaJSON string) map[string]interface{} {
+	var schema map[string]interface{}
+	Expect(json.Unmarshal([]byte(schemaJSON), &schema)).To(Succeed())
+	return schema["definitions"].(map[string]interface{})
+}
+
+// expectUnchanged calls f concurrently on the same definitions, checks that they are not changed,
+// and that every call gives the same result. Data races are found with go test -race.
+func expectUnchanged(definitions map[string]interface{}, f func(definitions map[string]interface{}) interface{}) {
+	before, err := json.Marshal(definitions)
+	Expect(err).To(BeNil())
+
+	const goroutines = 8
+	results := make([][]byte, goroutines)
+	var wg sync.WaitGroup
+	for i := 0; i < goroutines; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i], _ = json.Marshal(f(definitions))
+		}(i)
+	}
+	wg.Wait()
+
+	after, err := json.Marshal(definitions)
+	Expect(err).To(BeNil())
+	Expect(after).To(MatchJSON(before))
+	for _, res := range results[1:] {
+		Expect(res).To(MatchJSON(results[0]))
+	}
+}
+
+var _ = Describe("Concurrent translation", func() {
+	opts := Options{
+		NamedExamples:    true,
+		GenerateExamples: true,
+		Keywords:         map[string]string{"goType": "x-go-type"},
+		OnDiagnostic:     func(Diagnostic) {},
+	}
+
+	It("should not change definitions translated to components", func() {
+		for _, schema := range []string{everyPassJSON, fixtures.DiscriminatorJSON} {
+			expectUnchanged(decodeDefinitions(schema), func(definitions map[string]interface{}) interface{} {
+				res, err := TranslateDefinitionsWithOptions(definitions, opts)
+				Expect(err).To(BeNil())
+				return res
+			})
+		}
+	})
+
+	It("should not change components translated back to definitions", func() {
+		components := TranslateDefinitions(decodeDefinitions(everyPassJSON))
+		expectUnchanged(components, func(components map[string]interface{}) interface{} {
+			return TranslateComponents(components)
+		})
+		expectUnchanged(components, func(components map[string]interface{}) interface{} {
+			res, err := TranslateComponentsWithOptions(components, Options{NullableAsTypeArray: true})
+			Expect(err).To(BeNil())
+			return res
+		})
+	})
+
+	It("should not change schemas of other exported functions", func() {
+		definitions := decodeDefinitions(everyPassJSON)
+		components := TranslateDefinitions(definitions)
+		expectUnchanged(definitions, func(definitions map[string]interface{}) interface{} {
+			return ExclusiveBoundsToBoolean(definitions)
+		})
+		expectUnchanged(components, func(components map[string]interface{}) interface{} {
+			return ExclusiveBoundsToNumeric(components)
+		})
+		expectUnchanged(components, func(components map[string]interface{}) interface{} {
+			return GenerateExample(components["Pet"], components, 1)
+		})
+		expectUnchanged(components, func(components map[string]interface{}) interface{} {
+			return Diff(components, TranslateDefinitions(definitions))
+		})
+	})
+
+	It("should not change input of translation traversal", func() {
+		expectUnchanged(decodeDefinitions(fixtures.DiscriminatorJSON), func(definitions map[string]interface{}) interface{} {
+			t := &translation{}
+			res := make(map[string]interface{})
+			for name, schema := range definitions {
+				res[name] = t.visit(componentsPrefix+name, schema)
+			}
+			return res
+		})
+	})
+})
@@ -0,0 +1,187 @@
+package jsonschema2openapi
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Keywords which describe schema, but do not change which instances are valid
+var annotationKeywords = map[string]bool{
+	"title":        true,
+	"description":  true,
+	"example":      true,
+	"externalDocs": true,
+	"x-comment":    true,
+	"x-examples":   true,
+}
+
+// deduplicate returns copy of schemas where every group of structurally equal schemas is merged into one,
+// and references to removed schemas are replaced with references to it. Returned map tells into which schema
+// every removed one was merged. Only whole components are compared: equal subschemas inside different
+// components are left where they are, and are not extracted into components of their own.
+func (t *translation) deduplicate(schemas map[string]interface{}) (map[string]interface{}, map[string]string, error) {
+	byClass := make(map[string][]string)
+	for name, class := range equalClasses(schemas, t.opts.DeduplicateIgnoreAnnotations) {
+		byClass[class] = append(byClass[class], name)
+	}
+	var groups [][]string
+	for _, names := range byClass {
+		if len(names) > 1 {
+			sort.Strings(names)
+			groups = append(groups, names)
+		}
+	}
+	sort.Slice(groups, func(i, j int) bool {
+		return groups[i][0] < groups[j][0]
+	})
+
+	merged := make(map[string]string)
+	for _, names := range groups {
+		survivor := names[0]
+		if t.opts.DeduplicateName != nil {
+			survivor = t.opts.DeduplicateName(names)
+			if !containsString(names, survivor) {
+				return nil, nil, fmt.Errorf("Error DeduplicateName returned %q. Not able to merge %s", survivor, strings.Join(names, ", "))
+			}
+		}
+		for _, name := range names {
+			if name != survivor {
+				merged[name] = survivor
+				t.report(componentsPrefix+escapeRefToken(name), "merged into equal component %s", survivor)
+			}
+		}
+	}
+	if len(merged) == 0 {
+		return schemas, merged, nil
+	}
+
+	res := make(map[string]interface{}, len(schemas)-len(merged))
+	for name, schema := range schemas {
+		if _, ok := merged[name]; !ok {
+			res[name] = mergeRefs(schema, true, merged)
+		}
+	}
+	return res, merged, nil
+}
+
+// mergeRefs returns copy of jsonData where references to components merged into others point to them.
+// inSchema tells if jsonData is schema, or other part of spec.
+func mergeRefs(jsonData interface{}, inSchema bool, merged map[string]string) interface{} {
+	if len(merged) == 0 {
+		return jsonData
+	}
+	return mapRefs(jsonData, inSchema, func(ref string) string {
+		return renameRef(ref, componentsPrefix, merged)
+	})
+}
+
+// equalClasses maps name of every schema to class of structurally equal schemas. Schemas are equal when
+// they are the same after references are replaced with classes of schemas they point to, so schemas
+// referencing different but equal schemas, and recursive ones, are equal too. Numbers are compared
+// by value, so 1, 1.0 and 1e0 are equal.
+// Classes are found by splitting all schemas into smaller classes, until classes do not change.
+func equalClasses(schemas map[string]interface{}, ignoreAnnotations bool) map[string]string {
+	forms := make(map[string]interface{}, len(schemas))
+	classes := make(map[string]string, len(schemas))
+	for name, schema := range schemas {
+		if ignoreAnnotations {
+			schema = mapSchemas(schema, withoutAnnotations)
+		}
+		forms[name] = canonicalNumbers(schema)
+		classes[name] = ""
+	}
+	count := 1
+	for {
+		next := make(map[string]string, len(forms))
+		distinct := make(map[string]bool, len(forms))
+		for name, form := range forms {
+			data, _ := encodeJSON(mapRefs(form, true, func(ref string) string {
+				if target, rest, ok := splitRef(ref, componentsPrefix); ok {
+					if class, ok := classes[target]; ok {
+						return componentsPrefix + class + rest
+					}
+				}
+				return ref
+			}))
+			// schema stays in its previous class, so classes are only split
+			sum := sha256.Sum256(append([]byte(classes[name]+"\n"), data...))
+			next[name] = hex.EncodeToString(sum[:])
+			distinct[next[name]] = true
+		}
+		classes = next
+		if len(distinct) == count {
+			return classes
+		}
+		count = len(distinct)
+	}
+}
+
+func withoutAnnotations(schema map[string]interface{}) map[string]interface{} {
+	for k := range schema {
+		if annotationKeywords[k] {
+			delete(schema, k)
+		}
+	}
+	return schema
+}
+
+// canonicalNumbers returns copy of jsonData where every number is written the same way as other numbers
+// equal to it
+func canonicalNumbers(jsonData interface{}) interface{} {
+	switch v := jsonData.(type) {
+	case map[string]interface{}:
+		res := make(map[string]interface{}, len(v))
+		for k, value := range v {
+			res[k] = canonicalNumbers(value)
+		}
+		return res
+	case []interface{}:
+		res := make([]interface{}, len(v))
+		for i, elem := range v {
+			res[i] = canonicalNumbers(elem)
+		}
+		return res
+	case json.Number:
+		return canonicalNumber(string(v))
+	case float64:
+		return canonicalNumber(strconv.FormatFloat(v, 'g', -1, 64))
+	case int:
+		return canonicalNumber(strconv.Itoa(v))
+	default:
+		return v
+	}
+}
+
+// canonicalNumber writes JSON number as integer without leading and trailing zeros, and exponent:
+// "-1.50" becomes "-15e-1". Numbers with exponents which do not fit int are returned as they are.
+func canonicalNumber(number string) json.Number {
+	negative := strings.HasPrefix(number, "-")
+	mantissa := strings.TrimPrefix(number, "-")
+	exponent := 0
+	if i := strings.IndexAny(mantissa, "eE"); i >= 0 {
+		e, err := strconv.Atoi(mantissa[i+1:])
+		if err != nil {
+			return json.Number(number)
+		}
+		mantissa, exponent = mantissa[:i], e
+	}
+	if i := strings.IndexByte(mantissa, '.'); i >= 0 {
+		exponent -= len(mantissa) - i - 1
+		mantissa = mantissa[:i] + mantissa[i+1:]
+	}
+	mantissa = strings.TrimLeft(mantissa, "0")
+	if mantissa == "" {
+		return "0"
+	}
+	digits := strings.TrimRight(mantissa, "0")
+	exponent += len(mantissa) - len(digits)
+	if negative {
+		digits = "-" + digits
+	}
+	return json.Number(digits + "e" + strconv.Itoa(exponent))
+}
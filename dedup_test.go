@@ -0,0 +1,186 @@
+package jsonschema2openapi
+
+import (
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+const duplicatesSchema = `{
+	"definitions": {
+		"v1.Money": {
+			"type": "object",
+			"description": "Money of v1",
+			"properties": {"amount": {"type": "integer"}, "currency": {"$ref": "#/definitions/v1.Currency"}}
+		},
+		"v2.Money": {
+			"type": "object",
+			"properties": {"amount": {"type": "integer"}, "currency": {"$ref": "#/definitions/v2.Currency"}}
+		},
+		"v1.Currency": {"type": "string", "enum": ["EUR", "USD"]},
+		"v2.Currency": {"type": "string", "enum": ["EUR", "USD"]},
+		"Price": {"type": "string", "enum": ["EUR", "UAH"]},
+		"v1.Node": {"properties": {"title": {"type": "string"}, "children": {"items": {"$ref": "#/definitions/v1.Node"}}}},
+		"v2.Node": {"properties": {"title": {"type": "string"}, "children": {"items": {"$ref": "#/definitions/v2.Node"}}}},
+		"Order": {
+			"properties": {
+				"total": {"$ref": "#/definitions/v2.Money"},
+				"tree": {"$ref": "#/definitions/v2.Node"}
+			}
+		}
+	}
+}`
+
+var _ = Describe("Deduplication", func() {
+	It("should merge structurally equal components and rewrite references", func() {
+		components, err := TranslateDefinitionsWithOptions(decodeDefinitions(duplicatesSchema), Options{Deduplicate: true})
+		Expect(err).To(BeNil())
+		Expect(components).To(HaveKey("v1.Currency"))
+		Expect(components).NotTo(HaveKey("v2.Currency"))
+		Expect(components).To(HaveKey("v1.Node"))
+		Expect(components).NotTo(HaveKey("v2.Node"))
+		Expect(components).To(HaveKey("Price"))
+		// descriptions differ
+		Expect(components).To(HaveKey("v1.Money"))
+		Expect(components).To(HaveKey("v2.Money"))
+		Expect(components["v2.Money"]).To(HaveKeyWithValue("properties", HaveKeyWithValue("currency", map[string]interface{}{
+			"$ref": "#/components/schemas/v1.Currency",
+		})))
+		Expect(components["Order"]).To(HaveKeyWithValue("properties", map[string]interface{}{
+			"total": map[string]interface{}{"$ref": "#/components/schemas/v2.Money"},
+			"tree":  map[string]interface{}{"$ref": "#/components/schemas/v1.Node"},
+		}))
+	})
+
+	It("should compare numbers by value", func() {
+		var definitions map[string]interface{}
+		Expect(decodeJSON([]byte(`{
+			"A": {"type": "number", "minimum": 1, "maximum": 150, "enum": [0, 1.5]},
+			"B": {"type": "number", "minimum": 1.0, "maximum": 1.5e2, "enum": [-0.0, 15E-1]},
+			"C": {"type": "number", "minimum": 1, "maximum": 150, "enum": [0, 1.05]}
+		}`), &definitions)).To(Succeed())
+		components, err := TranslateDefinitionsWithOptions(definitions, Options{Deduplicate: true})
+		Expect(err).To(BeNil())
+		Expect(components).To(HaveKey("A"))
+		Expect(components).NotTo(HaveKey("B"))
+		Expect(components).To(HaveKey("C"))
+	})
+
+	It("should ignore annotations when asked", func() {
+		var diagnostics []string
+		components, err := TranslateDefinitionsWithOptions(decodeDefinitions(duplicatesSchema), Options{
+			Deduplicate:                  true,
+			DeduplicateIgnoreAnnotations: true,
+			DeduplicateName: func(names []string) string {
+				return names[len(names)-1]
+			},
+			OnDiagnostic: func(d Diagnostic) {
+				diagnostics = append(diagnostics, d.String())
+			},
+		})
+		Expect(err).To(BeNil())
+		Expect(components).To(HaveLen(5))
+		Expect(components).To(HaveKey("v2.Money"))
+		Expect(components).To(HaveKey("v2.Currency"))
+		// property named title is not annotation
+		Expect(components["v2.Node"]).To(HaveKeyWithValue("properties", HaveKey("title")))
+		Expect(components["Order"]).To(HaveKeyWithValue("properties", map[string]interface{}{
+			"total": map[string]interface{}{"$ref": "#/components/schemas/v2.Money"},
+			"tree":  map[string]interface{}{"$ref": "#/components/schemas/v2.Node"},
+		}))
+		Expect(diagnostics).To(Equal([]string{
+			"#/components/schemas/v1.Currency: merged into equal component v2.Currency",
+			"#/components/schemas/v1.Money: merged into equal component v2.Money",
+			"#/components/schemas/v1.Node: merged into equal component v2.Node",
+		}))
+	})
+
+	It("should fail when chosen name is not one of equal components", func() {
+		_, err := TranslateDefinitionsWithOptions(decodeDefinitions(duplicatesSchema), Options{
+			Deduplicate: true,
+			DeduplicateName: func(names []string) string {
+				return "Money"
+			},
+		})
+		Expect(err).To(MatchError(`Error DeduplicateName returned "Money". Not able to merge v1.Currency, v2.Currency`))
+	})
+
+	It("should not rewrite references inside examples, defaults and extensions", func() {
+		api, err := PutSchemaIntoOpenAPIWithOptions(`{
+			"definitions": {
+				"A": {"type": "string"},
+				"B": {"type": "string"},
+				"C": {
+					"properties": {
+						"b": {"$ref": "#/definitions/B"},
+						"discriminator": {"$ref": "#/definitions/B"}
+					},
+					"default": {"b": {"$ref": "#/components/schemas/B"}},
+					"x-ref": {"$ref": "#/components/schemas/B"}
+				}
+			}
+		}`, `{
+			"paths": {"/c": {"get": {"responses": {"default": {"content": {"application/json": {
+				"schema": {"$ref": "#/components/schemas/B"},
+				"example": {"$ref": "#/components/schemas/B"}
+			}}}}}}},
+			"components": {"schemas": {}}
+		}`, Options{Deduplicate: true})
+		Expect(err).To(BeNil())
+
+		jq := Jq(api)
+		Expect(jq.String("components", "schemas", "C", "properties", "b", "$ref")).To(Equal("#/components/schemas/A"))
+		Expect(jq.String("components", "schemas", "C", "properties", "discriminator", "$ref")).To(Equal("#/components/schemas/A"))
+		Expect(jq.String("components", "schemas", "C", "default", "b", "$ref")).To(Equal("#/components/schemas/B"))
+		Expect(jq.String("components", "schemas", "C", "x-ref", "$ref")).To(Equal("#/components/schemas/B"))
+		content := []string{"paths", "/c", "get", "responses", "default", "content", "application/json"}
+		Expect(jq.String(append(content, "schema", "$ref")...)).To(Equal("#/components/schemas/A"))
+		Expect(jq.String(append(content, "example", "$ref")...)).To(Equal("#/components/schemas/B"))
+	})
+
+	It("should rewrite references of discriminator mapping and template", func() {
+		template := strings.Replace(refsTemplate, "#/components/schemas/Pet", "#/components/schemas/v2.Event", 1)
+		api, err := PutSchemaIntoOpenAPIWithOptions(`{
+			"definitions": {
+				"v1.Event": {"oneOf": [
+					{"if": {"properties": {"version": {"enum": ["v1"]}}}, "then": {"$ref": "#/definitions/v1.Created"}, "else": {"properties": {"version": {"enum": ["v1"]}}}}
+				]},
+				"v2.Event": {"oneOf": [
+					{"if": {"properties": {"version": {"enum": ["v1"]}}}, "then": {"$ref": "#/definitions/v2.Created"}, "else": {"properties": {"version": {"enum": ["v1"]}}}}
+				]},
+				"v1.Created": {"type": "object"},
+				"v2.Created": {"type": "object"},
+				"Limit": {"type": "integer"},
+				"Error": {"type": "object", "properties": {"message": {"type": "string"}}}
+			}
+		}`, template, Options{Deduplicate: true, CheckRefs: true})
+		Expect(err).To(BeNil())
+		Expect(api).To(ContainSubstring(`"$ref": "#/components/schemas/v1.Event"`))
+		Expect(api).To(ContainSubstring(`"v1": "#/components/schemas/v1.Created"`))
+		Expect(api).NotTo(ContainSubstring(`v2.`))
+	})
+
+	It("should rewrite references of tuples and pattern properties kept in extensions", func() {
+		api, err := PutSchemaIntoOpenAPIWithOptions(`{
+			"definitions": {
+				"A": {"type": "object"},
+				"B": {"type": "object"},
+				"T": {"items": [{"$ref": "#/definitions/B"}, {"type": "string"}]},
+				"P": {"patternProperties": {"^b": {"$ref": "#/definitions/B"}}}
+			}
+		}`, `{
+			"paths": {"/t": {"get": {"responses": {"default": {"content": {"application/json": {
+				"schema": {"anyOf": [{"$ref": "#/components/schemas/T"}, {"$ref": "#/components/schemas/P"}]}
+			}}}}}}},
+			"components": {"schemas": {}}
+		}`, Options{Deduplicate: true, CheckRefs: true, PruneUnreachable: true})
+		Expect(err).To(BeNil())
+
+		jq := Jq(api)
+		Expect(jq.String("components", "schemas", "T", "items", "anyOf", "0", "$ref")).To(Equal("#/components/schemas/A"))
+		Expect(jq.String("components", "schemas", "T", "x-tuple", "items", "0", "$ref")).To(Equal("#/components/schemas/A"))
+		Expect(jq.String("components", "schemas", "P", "x-patternProperties", "^b", "$ref")).To(Equal("#/components/schemas/A"))
+		Expect(api).NotTo(ContainSubstring(`"#/components/schemas/B"`))
+	})
+})
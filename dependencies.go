@@ -0,0 +1,72 @@
+package jsonschema2openapi
+
+import "sort"
+
+// translateDependencies replaces "dependencies", "dependentRequired" and "dependentSchemas" with material implications:
+//
+//	"dependentRequired": { "a": ["b"] }
+//
+// becomes
+//
+//	"anyOf": [ { "not": { "required": ["a"] } }, { "required": ["a", "b"] } ]
+//
+// and
+//
+//	"dependentSchemas": { "a": SCHEMA }
+//
+// becomes
+//
+//	"anyOf": [ { "not": { "required": ["a"] } }, SCHEMA ]
+//
+// Draft-07 "dependencies" could contain both forms. Dependency is condition without "else", turned into anyOf
+// by materialImplication.
+func translateDependencies(schema map[string]interface{}) map[string]interface{} {
+	for _, keyword := range []string{"dependencies", "dependentRequired", "dependentSchemas"} {
+		dependencies, ok := schema[keyword].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		delete(schema, keyword)
+
+		properties := make([]string, 0, len(dependencies))
+		for k := range dependencies {
+			properties = append(properties, k)
+		}
+		sort.Strings(properties)
+		for _, property := range properties {
+			condition := map[string]interface{}{
+				"required": []interface{}{property},
+			}
+			var consequence interface{}
+			if required, ok := dependencies[property].([]interface{}); ok {
+				consequence = map[string]interface{}{
+					"required": append([]interface{}{property}, required...),
+				}
+			} else {
+				consequence = dependencies[property]
+			}
+			// the same as "if" without "else", added to anyOf of schema by materialImplication
+			implied := materialImplication(map[string]interface{}{
+				"if":   condition,
+				"then": consequence,
+			})
+			if anyOf, ok := implied["anyOf"].([]interface{}); ok {
+				addAnyOf(schema, anyOf)
+			}
+		}
+	}
+	return schema
+}
+
+// addAnyOf sets anyOf of schema, and if there is already one, combines them with allOf
+func addAnyOf(schema map[string]interface{}, anyOf []interface{}) {
+	if _, ok := schema["anyOf"]; !ok {
+		schema["anyOf"] = anyOf
+		return
+	}
+	allOf, _ := schema["allOf"].([]interface{})
+	// copied, so that array of allOf shared with other schema is not changed
+	schema["allOf"] = append(append([]interface{}{}, allOf...), map[string]interface{}{
+		"anyOf": anyOf,
+	})
+}
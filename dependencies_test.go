@@ -0,0 +1,116 @@
+package jsonschema2openapi
+
+import (
+	"encoding/json"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Dependencies", func() {
+	It("should be replaced with implications", func() {
+		var definitions map[string]interface{}
+		Expect(json.Unmarshal([]byte(`{
+			"Card": {
+				"anyOf": [ { "required": ["number"] }, { "required": ["token"] } ],
+				"dependencies": {
+					"number": ["expiry"],
+					"token": { "properties": { "provider": { "type": "string" } } }
+				},
+				"dependentRequired": { "billing": ["address"] }
+			}
+		}`), &definitions)).To(Succeed())
+
+		res, err := json.Marshal(TranslateDefinitions(definitions))
+		Expect(err).To(BeNil())
+		Expect(res).To(MatchJSON(`{
+			"Card": {
+				"anyOf": [ { "required": ["number"] }, { "required": ["token"] } ],
+				"allOf": [
+					{ "anyOf": [
+						{ "not": { "required": ["number"] } },
+						{ "required": ["number", "expiry"] }
+					] },
+					{ "anyOf": [
+						{ "not": { "required": ["token"] } },
+						{ "properties": { "provider": { "type": "string" } } }
+					] },
+					{ "anyOf": [
+						{ "not": { "required": ["billing"] } },
+						{ "required": ["billing", "address"] }
+					] }
+				]
+			}
+		}`))
+	})
+
+	It("should translate dependent schemas", func() {
+		res := TranslateDefinitions(map[string]interface{}{
+			"Card": map[string]interface{}{
+				"dependentSchemas": map[string]interface{}{
+					"number": map[string]interface{}{"required": []interface{}{"expiry"}},
+				},
+			},
+		})
+		Expect(res).To(Equal(map[string]interface{}{
+			"Card": map[string]interface{}{
+				"anyOf": []interface{}{
+					map[string]interface{}{"not": map[string]interface{}{"required": []interface{}{"number"}}},
+					map[string]interface{}{"required": []interface{}{"expiry"}},
+				},
+			},
+		}))
+	})
+
+	It("should not mistake names of properties for keywords", func() {
+		var definitions map[string]interface{}
+		Expect(json.Unmarshal([]byte(`{
+			"Card": {
+				"dependentRequired": { "a": ["b"], "items": ["c", "d"], "examples": ["e"] },
+				"dependencies": { "type": ["f"] }
+			}
+		}`), &definitions)).To(Succeed())
+
+		for _, policy := range []KeywordPolicy{PrefixUnknown, RejectUnknown} {
+			components, err := TranslateDefinitionsWithOptions(definitions, Options{UnknownKeywords: policy})
+			Expect(err).To(BeNil())
+			res, err := json.Marshal(components)
+			Expect(err).To(BeNil())
+			Expect(res).To(MatchJSON(`{
+				"Card": {
+					"anyOf": [ { "not": { "required": ["type"] } }, { "required": ["type", "f"] } ],
+					"allOf": [
+						{ "anyOf": [ { "not": { "required": ["a"] } }, { "required": ["a", "b"] } ] },
+						{ "anyOf": [ { "not": { "required": ["examples"] } }, { "required": ["examples", "e"] } ] },
+						{ "anyOf": [ { "not": { "required": ["items"] } }, { "required": ["items", "c", "d"] } ] }
+					]
+				}
+			}`))
+		}
+	})
+
+	It("should be the same implications as conditions without else", func() {
+		condition := func(keyword string) map[string]interface{} {
+			return map[string]interface{}{
+				"if":    map[string]interface{}{"required": []interface{}{"number"}},
+				keyword: map[string]interface{}{"required": []interface{}{"expiry"}},
+			}
+		}
+		res := TranslateDefinitions(map[string]interface{}{
+			"Dependent": map[string]interface{}{
+				"dependentSchemas": map[string]interface{}{
+					"number": map[string]interface{}{"required": []interface{}{"expiry"}},
+				},
+			},
+			"Then": condition("then"),
+			"Else": condition("else"),
+		})
+		Expect(res["Then"]).To(Equal(res["Dependent"]))
+		Expect(res["Else"]).To(Equal(map[string]interface{}{
+			"anyOf": []interface{}{
+				map[string]interface{}{"required": []interface{}{"number"}},
+				map[string]interface{}{"required": []interface{}{"expiry"}},
+			},
+		}))
+	})
+})
@@ -0,0 +1,543 @@
+package jsonschema2openapi
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strconv"
+
+	"github.com/jmoiron/jsonq"
+)
+
+// ChangeKind classifies Change between two versions of schema
+type ChangeKind string
+
+// Kinds of changes found by Diff
+const (
+	ComponentAdded       ChangeKind = "component-added"
+	ComponentRemoved     ChangeKind = "component-removed"
+	PropertyAdded        ChangeKind = "property-added"
+	PropertyRemoved      ChangeKind = "property-removed"
+	RequiredAdded        ChangeKind = "required-added"
+	RequiredRemoved      ChangeKind = "required-removed"
+	EnumNarrowed         ChangeKind = "enum-narrowed"
+	EnumWidened          ChangeKind = "enum-widened"
+	TypeChanged          ChangeKind = "type-changed"
+	FormatChanged        ChangeKind = "format-changed"
+	NullableRemoved      ChangeKind = "nullable-removed"
+	NullableAdded        ChangeKind = "nullable-added"
+	RefChanged           ChangeKind = "ref-changed"
+	DiscriminatorChanged ChangeKind = "discriminator-changed"
+	MappingAdded         ChangeKind = "mapping-added"
+	MappingRemoved       ChangeKind = "mapping-removed"
+	MappingChanged       ChangeKind = "mapping-changed"
+	// ConstraintAdded is items, additionalProperties, not or allOf schema added, additional properties
+	// forbidden or restricted, or anyOf or oneOf added where there was none, so fewer values are valid
+	ConstraintAdded ChangeKind = "constraint-added"
+	// ConstraintRemoved is the opposite of ConstraintAdded, so more values are valid
+	ConstraintRemoved ChangeKind = "constraint-removed"
+	// AlternativeAdded is branch added to anyOf or oneOf, AlternativeRemoved is branch removed from it
+	AlternativeAdded   ChangeKind = "alternative-added"
+	AlternativeRemoved ChangeKind = "alternative-removed"
+	// ConstraintTightened is bound like minimum, maxLength or minItems added, raised or lowered so fewer values
+	// are valid, or bound made exclusive, or pattern added
+	ConstraintTightened ChangeKind = "constraint-tightened"
+	// ConstraintLoosened is the opposite of ConstraintTightened, so more values are valid
+	ConstraintLoosened ChangeKind = "constraint-loosened"
+	// PatternChanged is pattern replaced with other one, which could both allow and reject values
+	PatternChanged ChangeKind = "pattern-changed"
+)
+
+// Direction tells whether schemas describe data which clients send, receive, or both.
+// It decides which changes break clients.
+type Direction string
+
+// Directions of data described by schemas
+const (
+	// BothDirections is default, every change which breaks requests or responses is breaking
+	BothDirections Direction = "both"
+	// Requests are sent by clients, so changes which reject data valid before break them
+	Requests Direction = "request"
+	// Responses are read by clients, so changes which allow data invalid before break them
+	Responses Direction = "response"
+)
+
+// Kinds of changes which break clients sending requests
+var requestBreakingChanges = map[ChangeKind]bool{
+	ComponentRemoved:     true,
+	PropertyRemoved:      true,
+	RequiredAdded:        true,
+	EnumNarrowed:         true,
+	TypeChanged:          true,
+	FormatChanged:        true,
+	NullableRemoved:      true,
+	RefChanged:           true,
+	DiscriminatorChanged: true,
+	MappingRemoved:       true,
+	MappingChanged:       true,
+	ConstraintAdded:      true,
+	AlternativeRemoved:   true,
+	ConstraintTightened:  true,
+	PatternChanged:       true,
+}
+
+// Kinds of changes which break clients reading responses
+var responseBreakingChanges = map[ChangeKind]bool{
+	ComponentRemoved:     true,
+	PropertyRemoved:      true,
+	RequiredRemoved:      true,
+	EnumWidened:          true,
+	TypeChanged:          true,
+	FormatChanged:        true,
+	NullableAdded:        true,
+	RefChanged:           true,
+	DiscriminatorChanged: true,
+	MappingAdded:         true,
+	MappingChanged:       true,
+	ConstraintRemoved:    true,
+	AlternativeAdded:     true,
+	ConstraintLoosened:   true,
+	PatternChanged:       true,
+}
+
+// breaks checks if change of kind breaks clients using schemas in direction
+func (direction Direction) breaks(kind ChangeKind) bool {
+	switch direction {
+	case Requests:
+		return requestBreakingChanges[kind]
+	case Responses:
+		return responseBreakingChanges[kind]
+	default:
+		return requestBreakingChanges[kind] || responseBreakingChanges[kind]
+	}
+}
+
+// Change is single difference between two versions of components/schemas
+type Change struct {
+	// Pointer is reference to changed schema, like "#/components/schemas/User/properties/name"
+	Pointer  string     `json:"pointer"`
+	Kind     ChangeKind `json:"kind"`
+	Breaking bool       `json:"breaking"`
+	Message  string     `json:"message"`
+}
+
+func (c Change) String() string {
+	severity := "non-breaking"
+	if c.Breaking {
+		severity = "breaking"
+	}
+	return fmt.Sprintf("%s: %s (%s): %s", c.Pointer, c.Kind, severity, c.Message)
+}
+
+// Diff compares two versions of translated components/schemas, and returns changes sorted by pointer.
+// Schemas are assumed to be used both in requests and responses.
+func Diff(old, new map[string]interface{}) []Change {
+	return DiffWithDirection(old, new, BothDirections)
+}
+
+// DiffWithDirection is Diff of schemas used in given direction. Empty direction is BothDirections.
+func DiffWithDirection(old, new map[string]interface{}, direction Direction) []Change {
+	d := differ{direction: direction}
+	for _, name := range unionKeys(old, new) {
+		pointer := componentsPrefix + escapeRefToken(name)
+		oldSchema, inOld := old[name]
+		newSchema, inNew := new[name]
+		switch {
+		case !inNew:
+			d.add(pointer, ComponentRemoved, "component %s is removed", name)
+		case !inOld:
+			d.add(pointer, ComponentAdded, "component %s is added", name)
+		default:
+			d.schema(pointer, oldSchema, newSchema)
+		}
+	}
+	sort.SliceStable(d.changes, func(i, j int) bool {
+		return d.changes[i].Pointer < d.changes[j].Pointer
+	})
+	return d.changes
+}
+
+// DiffOpenAPI compares components/schemas of two OpenAPI specs
+func DiffOpenAPI(oldSpec, newSpec string) ([]Change, error) {
+	return DiffOpenAPIWithDirection(oldSpec, newSpec, BothDirections)
+}
+
+// DiffOpenAPIWithDirection is DiffOpenAPI of schemas used in given direction
+func DiffOpenAPIWithDirection(oldSpec, newSpec string, direction Direction) ([]Change, error) {
+	old, err := specComponents(oldSpec)
+	if err != nil {
+		return nil, err
+	}
+	new, err := specComponents(newSpec)
+	if err != nil {
+		return nil, err
+	}
+	return DiffWithDirection(old, new, direction), nil
+}
+
+// ReadComponents reads components/schemas of OpenAPI spec from r, or definitions of JSON Schema, which are
+// translated with opts, to be compared by Diff. Input is decoded like by Convert: only up to opts.MaxInputSize
+// and opts.MaxDepth, with numbers kept exact, and nothing but whitespace is allowed after it.
+func ReadComponents(ctx context.Context, r io.Reader, opts Options) (map[string]interface{}, error) {
+	doc, _, err := decodeInput(ctx, r, "OpenAPI spec or JSON schema", opts)
+	if err != nil {
+		return nil, inputError(ctx, err, "Error %s. Not able to parse OpenAPI spec or JSON schema")
+	}
+	if components, ok := doc["components"].(map[string]interface{}); ok {
+		schemas, _ := components["schemas"].(map[string]interface{})
+		return schemas, nil
+	}
+	definitions, ok := doc["definitions"].(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("Bad OpenAPI spec or JSON schema, it has neither components/schemas nor definitions")
+	}
+	return TranslateDefinitionsWithOptions(definitions, opts)
+}
+
+func specComponents(openAPISpec string) (map[string]interface{}, error) {
+	var spec map[string]interface{}
+	err := decodeJSON([]byte(openAPISpec), &spec)
+	if err != nil {
+		return nil, fmt.Errorf("Error %s. Not able to parse OpenAPI spec", err.Error())
+	}
+	components, err := jsonq.NewQuery(spec).Object("components", "schemas")
+	if err != nil {
+		return nil, fmt.Errorf("Error %s. Bad OpenAPI spec, no component.schemas object", err.Error())
+	}
+	return components, nil
+}
+
+// HasBreaking checks if any of changes breaks clients
+func HasBreaking(changes []Change) bool {
+	for _, c := range changes {
+		if c.Breaking {
+			return true
+		}
+	}
+	return false
+}
+
+type differ struct {
+	direction Direction
+	changes   []Change
+}
+
+func (d *differ) add(pointer string, kind ChangeKind, format string, args ...interface{}) {
+	d.addBreaking(pointer, kind, d.direction.breaks(kind), format, args...)
+}
+
+// addBreaking adds change, which breaks clients or not regardless of its kind
+func (d *differ) addBreaking(pointer string, kind ChangeKind, breaking bool, format string, args ...interface{}) {
+	d.changes = append(d.changes, Change{
+		Pointer:  pointer,
+		Kind:     kind,
+		Breaking: breaking,
+		Message:  fmt.Sprintf(format, args...),
+	})
+}
+
+func (d *differ) schema(pointer string, oldData, newData interface{}) {
+	old, _ := oldData.(map[string]interface{})
+	new, _ := newData.(map[string]interface{})
+	if old == nil || new == nil {
+		return
+	}
+
+	if oldRef, newRef := toString(old["$ref"]), toString(new["$ref"]); oldRef != newRef {
+		d.add(pointer, RefChanged, "reference changed from %q to %q", oldRef, newRef)
+		return
+	}
+	if !reflect.DeepEqual(old["type"], new["type"]) {
+		d.add(pointer, TypeChanged, "type changed from %v to %v", old["type"], new["type"])
+	}
+	if !reflect.DeepEqual(old["format"], new["format"]) {
+		d.add(pointer, FormatChanged, "format changed from %v to %v", old["format"], new["format"])
+	}
+	if oldNullable, newNullable := old["nullable"] == true, new["nullable"] == true; oldNullable && !newNullable {
+		d.add(pointer, NullableRemoved, "value is not nullable anymore")
+	} else if !oldNullable && newNullable {
+		d.add(pointer, NullableAdded, "value became nullable")
+	}
+	d.enum(pointer, old, new)
+	d.required(pointer, old, new)
+	d.discriminator(pointer, old, new)
+	for _, b := range exclusiveBounds {
+		d.numericBound(pointer, b.inclusive, b.exclusive, b.lower, old, new)
+	}
+	for _, b := range lengthBounds {
+		d.bound(pointer+"/"+b.keyword, b.keyword, b.lower, old[b.keyword], false, new[b.keyword], false)
+	}
+	d.pattern(pointer, old, new)
+
+	oldProperties, _ := old["properties"].(map[string]interface{})
+	newProperties, _ := new["properties"].(map[string]interface{})
+	for _, name := range unionKeys(oldProperties, newProperties) {
+		p := pointer + "/properties/" + escapeRefToken(name)
+		oldProperty, inOld := oldProperties[name]
+		newProperty, inNew := newProperties[name]
+		switch {
+		case !inNew:
+			d.add(p, PropertyRemoved, "property %s is removed", name)
+		case !inOld:
+			d.addBreaking(p, PropertyAdded, d.propertyAddedBreaks(name, new), "property %s is added", name)
+		default:
+			d.schema(p, oldProperty, newProperty)
+		}
+	}
+
+	for _, keyword := range []string{"items", "not"} {
+		d.subschema(pointer+"/"+keyword, keyword, old[keyword], new[keyword])
+	}
+	d.additionalProperties(pointer+"/additionalProperties", old["additionalProperties"], new["additionalProperties"])
+	for _, keyword := range []string{"allOf", "anyOf", "oneOf"} {
+		d.composition(pointer+"/"+keyword, keyword, old[keyword], new[keyword])
+	}
+}
+
+// propertyAddedBreaks checks if adding property to schema breaks clients: when property is required too,
+// or when schema does not allow additional properties and is used in requests
+func (d *differ) propertyAddedBreaks(name string, schema map[string]interface{}) bool {
+	required, _ := schema["required"].([]interface{})
+	if containsJSON(required, name) {
+		return true
+	}
+	return d.direction != Responses && schema["additionalProperties"] == false
+}
+
+// Keywords of integer bounds, and whether bound is lower
+var lengthBounds = []struct {
+	keyword string
+	lower   bool
+}{
+	{"minLength", true},
+	{"maxLength", false},
+	{"minItems", true},
+	{"maxItems", false},
+	{"minProperties", true},
+	{"maxProperties", false},
+}
+
+// numericBound compares minimum or maximum, which is exclusive when exclusive keyword is true.
+// Numeric exclusive keyword, without inclusive one, is taken as exclusive bound too.
+func (d *differ) numericBound(pointer, inclusive, exclusive string, lower bool, old, new map[string]interface{}) {
+	value := func(schema map[string]interface{}) (interface{}, bool) {
+		if _, ok := toRat(schema[exclusive]); ok && schema[inclusive] == nil {
+			return schema[exclusive], true
+		}
+		return schema[inclusive], schema[exclusive] == true
+	}
+	oldValue, oldExclusive := value(old)
+	newValue, newExclusive := value(new)
+	d.bound(pointer+"/"+inclusive, inclusive, lower, oldValue, oldExclusive, newValue, newExclusive)
+}
+
+// bound compares lower or upper bound of keyword, which is missing when value is not a number
+func (d *differ) bound(pointer, keyword string, lower bool, oldValue interface{}, oldExclusive bool, newValue interface{}, newExclusive bool) {
+	_, inOld := toRat(oldValue)
+	_, inNew := toRat(newValue)
+	describe := func(value interface{}, exclusive bool) string {
+		if exclusive {
+			return fmt.Sprintf("%v (exclusive)", value)
+		}
+		return fmt.Sprint(value)
+	}
+	switch {
+	case !inOld && !inNew:
+	case !inOld:
+		d.add(pointer, ConstraintTightened, "%s %s is added", keyword, describe(newValue, newExclusive))
+	case !inNew:
+		d.add(pointer, ConstraintLoosened, "%s %s is removed", keyword, describe(oldValue, oldExclusive))
+	default:
+		cmp, _ := compareNumbers(newValue, oldValue)
+		if cmp == 0 && oldExclusive == newExclusive {
+			return
+		}
+		var tighter bool
+		switch {
+		case cmp == 0:
+			tighter = newExclusive // the same bound became exclusive
+		case lower:
+			tighter = cmp > 0
+		default:
+			tighter = cmp < 0
+		}
+		kind := ConstraintLoosened
+		if tighter {
+			kind = ConstraintTightened
+		}
+		d.add(pointer, kind, "%s changed from %s to %s", keyword, describe(oldValue, oldExclusive), describe(newValue, newExclusive))
+	}
+}
+
+func (d *differ) pattern(pointer string, old, new map[string]interface{}) {
+	oldPattern, inOld := old["pattern"].(string)
+	newPattern, inNew := new["pattern"].(string)
+	p := pointer + "/pattern"
+	switch {
+	case oldPattern == newPattern && inOld == inNew:
+	case !inOld:
+		d.add(p, ConstraintTightened, "pattern %q is added", newPattern)
+	case !inNew:
+		d.add(p, ConstraintLoosened, "pattern %q is removed", oldPattern)
+	default:
+		d.add(p, PatternChanged, "pattern changed from %q to %q", oldPattern, newPattern)
+	}
+}
+
+// subschema compares schemas of keyword like items, which restricts values when it is present
+func (d *differ) subschema(pointer, keyword string, oldSchema, newSchema interface{}) {
+	switch {
+	case oldSchema == nil && newSchema == nil:
+	case oldSchema == nil:
+		d.add(pointer, ConstraintAdded, "%s is added", keyword)
+	case newSchema == nil:
+		d.add(pointer, ConstraintRemoved, "%s is removed", keyword)
+	default:
+		d.schema(pointer, oldSchema, newSchema)
+	}
+}
+
+// additionalProperties compares additionalProperties, which are allowed when they are missing, true or empty schema
+func (d *differ) additionalProperties(pointer string, oldValue, newValue interface{}) {
+	oldValue, newValue = allowsAny(oldValue), allowsAny(newValue)
+	_, oldSchema := oldValue.(map[string]interface{})
+	_, newSchema := newValue.(map[string]interface{})
+	switch {
+	case oldSchema && newSchema:
+		d.schema(pointer, oldValue, newValue)
+	case oldValue == newValue:
+	case newValue == false:
+		d.add(pointer, ConstraintAdded, "additional properties are not allowed anymore")
+	case oldValue == false:
+		d.add(pointer, ConstraintRemoved, "additional properties are allowed")
+	case newSchema:
+		d.add(pointer, ConstraintAdded, "additional properties are restricted by schema")
+	default:
+		d.add(pointer, ConstraintRemoved, "additional properties are not restricted by schema anymore")
+	}
+}
+
+// allowsAny replaces missing or empty schema of additionalProperties with true
+func allowsAny(additionalProperties interface{}) interface{} {
+	if schema, ok := additionalProperties.(map[string]interface{}); additionalProperties == nil || ok && len(schema) == 0 {
+		return true
+	}
+	return additionalProperties
+}
+
+// composition compares branches of allOf, anyOf or oneOf with the same indexes. Branch added to allOf restricts
+// values, branch added to anyOf or oneOf allows more of them, unless there were no branches before.
+func (d *differ) composition(pointer, keyword string, oldData, newData interface{}) {
+	oldSchemas, _ := oldData.([]interface{})
+	newSchemas, _ := newData.([]interface{})
+	for i := 0; i < len(oldSchemas) && i < len(newSchemas); i++ {
+		d.schema(pointer+"/"+strconv.Itoa(i), oldSchemas[i], newSchemas[i])
+	}
+	added, removed := AlternativeAdded, AlternativeRemoved
+	if keyword == "allOf" || len(oldSchemas) == 0 || len(newSchemas) == 0 {
+		// branch of allOf, or anyOf or oneOf itself, restricts values
+		added, removed = ConstraintAdded, ConstraintRemoved
+	}
+	for i := len(oldSchemas); i < len(newSchemas); i++ {
+		d.add(pointer+"/"+strconv.Itoa(i), added, "branch %d is added to %s", i, keyword)
+	}
+	for i := len(newSchemas); i < len(oldSchemas); i++ {
+		d.add(pointer+"/"+strconv.Itoa(i), removed, "branch %d is removed from %s", i, keyword)
+	}
+}
+
+func (d *differ) enum(pointer string, old, new map[string]interface{}) {
+	oldEnum, inOld := old["enum"].([]interface{})
+	newEnum, inNew := new["enum"].([]interface{})
+	if !inOld && !inNew {
+		return
+	}
+	if !inNew {
+		d.add(pointer+"/enum", EnumWidened, "enum is removed")
+		return
+	}
+	if !inOld {
+		d.add(pointer+"/enum", EnumNarrowed, "enum %v is added", newEnum)
+		return
+	}
+	if removed := missing(oldEnum, newEnum); len(removed) > 0 {
+		d.add(pointer+"/enum", EnumNarrowed, "values %v are removed from enum", removed)
+	}
+	if added := missing(newEnum, oldEnum); len(added) > 0 {
+		d.add(pointer+"/enum", EnumWidened, "values %v are added to enum", added)
+	}
+}
+
+func (d *differ) required(pointer string, old, new map[string]interface{}) {
+	oldRequired, _ := old["required"].([]interface{})
+	newRequired, _ := new["required"].([]interface{})
+	for _, name := range missing(newRequired, oldRequired) {
+		d.add(pointer+"/required", RequiredAdded, "property %v became required", name)
+	}
+	for _, name := range missing(oldRequired, newRequired) {
+		d.add(pointer+"/required", RequiredRemoved, "property %v is not required anymore", name)
+	}
+}
+
+func (d *differ) discriminator(pointer string, old, new map[string]interface{}) {
+	oldDiscriminator, inOld := old["discriminator"].(map[string]interface{})
+	newDiscriminator, inNew := new["discriminator"].(map[string]interface{})
+	if !inOld && !inNew {
+		return
+	}
+	p := pointer + "/discriminator"
+	if !inOld || !inNew || oldDiscriminator["propertyName"] != newDiscriminator["propertyName"] {
+		d.add(p, DiscriminatorChanged, "discriminator property changed from %v to %v",
+			oldDiscriminator["propertyName"], newDiscriminator["propertyName"])
+		return
+	}
+	oldMapping, _ := oldDiscriminator["mapping"].(map[string]interface{})
+	newMapping, _ := newDiscriminator["mapping"].(map[string]interface{})
+	for _, value := range unionKeys(oldMapping, newMapping) {
+		oldRef, inOld := oldMapping[value]
+		newRef, inNew := newMapping[value]
+		switch {
+		case !inNew:
+			d.add(p+"/mapping", MappingRemoved, "value %s is not mapped anymore", value)
+		case !inOld:
+			d.add(p+"/mapping", MappingAdded, "value %s is mapped to %v", value, newRef)
+		case oldRef != newRef:
+			d.add(p+"/mapping", MappingChanged, "value %s is mapped to %v instead of %v", value, newRef, oldRef)
+		}
+	}
+}
+
+// missing returns values which are in a, but not in b
+func missing(a, b []interface{}) []interface{} {
+	var res []interface{}
+	for _, x := range a {
+		found := false
+		for _, y := range b {
+			if reflect.DeepEqual(x, y) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			res = append(res, x)
+		}
+	}
+	return res
+}
+
+// unionKeys returns sorted keys of both objects
+func unionKeys(a, b map[string]interface{}) []string {
+	keys := make([]string, 0, len(a)+len(b))
+	for k := range a {
+		keys = append(keys, k)
+	}
+	for k := range b {
+		if _, ok := a[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,347 @@
+package jsonschema2openapi
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func diffJSON(oldJSON, newJSON string) []Change {
+	var old, new map[string]interface{}
+	Expect(json.Unmarshal([]byte(oldJSON), &old)).To(Succeed())
+	Expect(json.Unmarshal([]byte(newJSON), &new)).To(Succeed())
+	return Diff(old, new)
+}
+
+func kinds(changes []Change) []ChangeKind {
+	res := make([]ChangeKind, 0, len(changes))
+	for _, c := range changes {
+		res = append(res, c.Kind)
+	}
+	return res
+}
+
+var _ = Describe("Diff", func() {
+	It("should find no changes in the same components", func() {
+		components := `{"User": {"type": "object", "properties": {"name": {"type": "string"}}}}`
+		Expect(diffJSON(components, components)).To(BeEmpty())
+	})
+
+	It("should classify added and removed components and properties", func() {
+		changes := diffJSON(`{
+			"User": {"type": "object", "properties": {"name": {"type": "string"}, "age": {"type": "integer"}}},
+			"Old": {"type": "string"}
+		}`, `{
+			"User": {"type": "object", "properties": {"name": {"type": "string"}, "email": {"type": "string"}}},
+			"New": {"type": "string"}
+		}`)
+		Expect(changes).To(Equal([]Change{
+			{Pointer: "#/components/schemas/New", Kind: ComponentAdded, Message: "component New is added"},
+			{Pointer: "#/components/schemas/Old", Kind: ComponentRemoved, Breaking: true, Message: "component Old is removed"},
+			{Pointer: "#/components/schemas/User/properties/age", Kind: PropertyRemoved, Breaking: true, Message: "property age is removed"},
+			{Pointer: "#/components/schemas/User/properties/email", Kind: PropertyAdded, Message: "property email is added"},
+		}))
+		Expect(HasBreaking(changes)).To(BeTrue())
+	})
+
+	It("should treat new required fields as breaking", func() {
+		changes := diffJSON(
+			`{"User": {"required": ["name", "age"]}}`,
+			`{"User": {"required": ["name", "email"]}}`,
+		)
+		Expect(kinds(changes)).To(Equal([]ChangeKind{RequiredAdded, RequiredRemoved}))
+		Expect(changes[0].Breaking).To(BeTrue())
+		// response readers could rely on age being present
+		Expect(changes[1].Breaking).To(BeTrue())
+	})
+
+	It("should classify enum changes", func() {
+		changes := diffJSON(
+			`{"Color": {"type": "string", "enum": ["red", "green"]}}`,
+			`{"Color": {"type": "string", "enum": ["red", "blue"]}}`,
+		)
+		Expect(kinds(changes)).To(Equal([]ChangeKind{EnumNarrowed, EnumWidened}))
+		Expect(changes[0].Message).To(Equal("values [green] are removed from enum"))
+
+		changes = diffJSON(
+			`{"Color": {"type": "string", "enum": ["red"]}}`,
+			`{"Color": {"type": "string", "enum": ["red", "blue"]}}`,
+		)
+		Expect(HasBreaking(changes)).To(BeTrue())
+	})
+
+	It("should classify changes by direction in which schemas are used", func() {
+		var old, new map[string]interface{}
+		Expect(json.Unmarshal([]byte(`{"User": {
+			"required": ["name", "age"],
+			"properties": {"role": {"enum": ["admin"]}, "email": {"type": "string", "nullable": true}, "nick": {}}
+		}}`), &old)).To(Succeed())
+		Expect(json.Unmarshal([]byte(`{"User": {
+			"required": ["name", "email"],
+			"properties": {"role": {"enum": ["admin", "guest"]}, "email": {"type": "string"}, "nick": {"nullable": true}}
+		}}`), &new)).To(Succeed())
+
+		breaking := func(direction Direction) map[ChangeKind]bool {
+			res := make(map[ChangeKind]bool)
+			for _, c := range DiffWithDirection(old, new, direction) {
+				res[c.Kind] = c.Breaking
+			}
+			return res
+		}
+		Expect(breaking(Requests)).To(Equal(map[ChangeKind]bool{
+			RequiredAdded: true, RequiredRemoved: false, NullableRemoved: true, NullableAdded: false, EnumWidened: false,
+		}))
+		Expect(breaking(Responses)).To(Equal(map[ChangeKind]bool{
+			RequiredAdded: false, RequiredRemoved: true, NullableRemoved: false, NullableAdded: true, EnumWidened: true,
+		}))
+		Expect(breaking(BothDirections)).To(Equal(map[ChangeKind]bool{
+			RequiredAdded: true, RequiredRemoved: true, NullableRemoved: true, NullableAdded: true, EnumWidened: true,
+		}))
+		Expect(breaking("")).To(Equal(breaking(BothDirections)))
+	})
+
+	It("should find type and nullable changes in nested schemas", func() {
+		changes := diffJSON(
+			`{"List": {"type": "array", "items": {"type": "integer", "nullable": true}}}`,
+			`{"List": {"type": "array", "items": {"type": "string"}}}`,
+		)
+		Expect(kinds(changes)).To(Equal([]ChangeKind{TypeChanged, NullableRemoved}))
+		Expect(changes[0].Pointer).To(Equal("#/components/schemas/List/items"))
+		Expect(changes[0].Message).To(Equal("type changed from integer to string"))
+	})
+
+	It("should classify added and removed subschemas", func() {
+		changes := diffJSON(`{
+			"List": {"type": "array"},
+			"Tags": {"type": "array", "items": {"type": "string"}},
+			"Map": {"additionalProperties": {"type": "string"}},
+			"Open": {"additionalProperties": true},
+			"Closed": {"additionalProperties": false},
+			"Empty": {"additionalProperties": {}}
+		}`, `{
+			"List": {"type": "array", "items": {"type": "string"}},
+			"Tags": {"type": "array"},
+			"Map": {},
+			"Open": {"additionalProperties": false},
+			"Closed": {"additionalProperties": {"type": "string"}},
+			"Empty": {}
+		}`)
+		Expect(changes).To(Equal([]Change{
+			{Pointer: "#/components/schemas/Closed/additionalProperties", Kind: ConstraintRemoved, Breaking: true, Message: "additional properties are allowed"},
+			{Pointer: "#/components/schemas/List/items", Kind: ConstraintAdded, Breaking: true, Message: "items is added"},
+			{Pointer: "#/components/schemas/Map/additionalProperties", Kind: ConstraintRemoved, Breaking: true, Message: "additional properties are not restricted by schema anymore"},
+			{Pointer: "#/components/schemas/Open/additionalProperties", Kind: ConstraintAdded, Breaking: true, Message: "additional properties are not allowed anymore"},
+			{Pointer: "#/components/schemas/Tags/items", Kind: ConstraintRemoved, Breaking: true, Message: "items is removed"},
+		}))
+		Expect(DiffWithDirection(map[string]interface{}{
+			"Open": map[string]interface{}{"additionalProperties": true},
+		}, map[string]interface{}{
+			"Open": map[string]interface{}{"additionalProperties": false},
+		}, Responses)[0].Breaking).To(BeFalse())
+	})
+
+	It("should classify added and removed branches by direction", func() {
+		var old, new map[string]interface{}
+		Expect(json.Unmarshal([]byte(`{
+			"All": {"allOf": [{"type": "object"}]},
+			"Any": {"anyOf": [{"type": "string"}, {"type": "integer"}]},
+			"One": {"oneOf": [{"type": "string"}]},
+			"Free": {}
+		}`), &old)).To(Succeed())
+		Expect(json.Unmarshal([]byte(`{
+			"All": {"allOf": [{"type": "object"}, {"required": ["id"]}]},
+			"Any": {"anyOf": [{"type": "string"}]},
+			"One": {"oneOf": [{"type": "string"}, {"type": "integer"}]},
+			"Free": {"oneOf": [{"type": "string"}]}
+		}`), &new)).To(Succeed())
+
+		breaking := func(direction Direction) map[string]bool {
+			res := make(map[string]bool)
+			for _, c := range DiffWithDirection(old, new, direction) {
+				res[c.Pointer+" "+string(c.Kind)] = c.Breaking
+			}
+			return res
+		}
+		Expect(breaking(Requests)).To(Equal(map[string]bool{
+			"#/components/schemas/All/allOf/1 constraint-added":    true,
+			"#/components/schemas/Any/anyOf/1 alternative-removed": true,
+			"#/components/schemas/Free/oneOf/0 constraint-added":   true,
+			"#/components/schemas/One/oneOf/1 alternative-added":   false,
+		}))
+		Expect(breaking(Responses)).To(Equal(map[string]bool{
+			"#/components/schemas/All/allOf/1 constraint-added":    false,
+			"#/components/schemas/Any/anyOf/1 alternative-removed": false,
+			"#/components/schemas/Free/oneOf/0 constraint-added":   false,
+			"#/components/schemas/One/oneOf/1 alternative-added":   true,
+		}))
+	})
+
+	It("should classify tightened and loosened constraints by direction", func() {
+		var old, new map[string]interface{}
+		Expect(decodeJSON([]byte(`{
+			"Age": {"type": "integer", "minimum": 0, "maximum": 150},
+			"Price": {"type": "number", "minimum": 0, "exclusiveMaximum": true, "maximum": 9007199254740993},
+			"Name": {"type": "string", "maxLength": 64, "pattern": "^[a-z]+$"},
+			"Code": {"type": "string", "pattern": "^[A-Z]+$"},
+			"Tags": {"type": "array", "minItems": 1}
+		}`), &old)).To(Succeed())
+		Expect(decodeJSON([]byte(`{
+			"Age": {"type": "integer", "minimum": 1, "maximum": 200},
+			"Price": {"type": "number", "minimum": 0, "exclusiveMinimum": true, "maximum": 9007199254740992},
+			"Name": {"type": "string", "maxLength": 64.0, "minLength": 1},
+			"Code": {"type": "string", "pattern": "^[A-Z0-9]+$"},
+			"Tags": {"type": "array", "maxItems": 10}
+		}`), &new)).To(Succeed())
+
+		changes := Diff(old, new)
+		Expect(changes).To(Equal([]Change{
+			{Pointer: "#/components/schemas/Age/maximum", Kind: ConstraintLoosened, Breaking: true, Message: "maximum changed from 150 to 200"},
+			{Pointer: "#/components/schemas/Age/minimum", Kind: ConstraintTightened, Breaking: true, Message: "minimum changed from 0 to 1"},
+			{Pointer: "#/components/schemas/Code/pattern", Kind: PatternChanged, Breaking: true, Message: `pattern changed from "^[A-Z]+$" to "^[A-Z0-9]+$"`},
+			{Pointer: "#/components/schemas/Name/minLength", Kind: ConstraintTightened, Breaking: true, Message: "minLength 1 is added"},
+			{Pointer: "#/components/schemas/Name/pattern", Kind: ConstraintLoosened, Breaking: true, Message: `pattern "^[a-z]+$" is removed`},
+			{Pointer: "#/components/schemas/Price/maximum", Kind: ConstraintTightened, Breaking: true, Message: "maximum changed from 9007199254740993 (exclusive) to 9007199254740992"},
+			{Pointer: "#/components/schemas/Price/minimum", Kind: ConstraintTightened, Breaking: true, Message: "minimum changed from 0 to 0 (exclusive)"},
+			{Pointer: "#/components/schemas/Tags/maxItems", Kind: ConstraintTightened, Breaking: true, Message: "maxItems 10 is added"},
+			{Pointer: "#/components/schemas/Tags/minItems", Kind: ConstraintLoosened, Breaking: true, Message: "minItems 1 is removed"},
+		}))
+
+		breaking := func(direction Direction) map[ChangeKind]bool {
+			res := make(map[ChangeKind]bool)
+			for _, c := range DiffWithDirection(old, new, direction) {
+				res[c.Kind] = c.Breaking
+			}
+			return res
+		}
+		Expect(breaking(Requests)).To(Equal(map[ChangeKind]bool{
+			ConstraintTightened: true, ConstraintLoosened: false, PatternChanged: true,
+		}))
+		Expect(breaking(Responses)).To(Equal(map[ChangeKind]bool{
+			ConstraintTightened: false, ConstraintLoosened: true, PatternChanged: true,
+		}))
+	})
+
+	It("should treat added properties as breaking when they are required, or not allowed before in requests", func() {
+		var old, new map[string]interface{}
+		Expect(json.Unmarshal([]byte(`{
+			"Required": {"properties": {}},
+			"Closed": {"properties": {}, "additionalProperties": false},
+			"Open": {"properties": {}}
+		}`), &old)).To(Succeed())
+		Expect(json.Unmarshal([]byte(`{
+			"Required": {"properties": {"id": {}}, "required": ["id"]},
+			"Closed": {"properties": {"id": {}}, "additionalProperties": false},
+			"Open": {"properties": {"id": {}}}
+		}`), &new)).To(Succeed())
+
+		breaking := func(direction Direction) map[string]bool {
+			res := make(map[string]bool)
+			for _, c := range DiffWithDirection(old, new, direction) {
+				if c.Kind == PropertyAdded {
+					res[c.Pointer] = c.Breaking
+				}
+			}
+			return res
+		}
+		Expect(breaking(Requests)).To(Equal(map[string]bool{
+			"#/components/schemas/Closed/properties/id":   true,
+			"#/components/schemas/Open/properties/id":     false,
+			"#/components/schemas/Required/properties/id": true,
+		}))
+		Expect(breaking(Responses)).To(Equal(map[string]bool{
+			"#/components/schemas/Closed/properties/id":   false,
+			"#/components/schemas/Open/properties/id":     false,
+			"#/components/schemas/Required/properties/id": true,
+		}))
+	})
+
+	It("should classify discriminator mapping changes", func() {
+		changes := diffJSON(`{
+			"Event": {
+				"oneOf": [{"$ref": "#/components/schemas/V1"}, {"$ref": "#/components/schemas/V2"}],
+				"discriminator": {"propertyName": "version", "mapping": {
+					"v1": "#/components/schemas/V1", "v2": "#/components/schemas/V2", "v3": "#/components/schemas/V3"
+				}}
+			}
+		}`, `{
+			"Event": {
+				"oneOf": [{"$ref": "#/components/schemas/V1"}, {"$ref": "#/components/schemas/V3"}],
+				"discriminator": {"propertyName": "version", "mapping": {
+					"v1": "#/components/schemas/V1", "v3": "#/components/schemas/V2", "v4": "#/components/schemas/V4"
+				}}
+			}
+		}`)
+		Expect(kinds(changes)).To(Equal([]ChangeKind{MappingRemoved, MappingChanged, MappingAdded, RefChanged}))
+		Expect(changes[3].Pointer).To(Equal("#/components/schemas/Event/oneOf/1"))
+
+		changes = diffJSON(
+			`{"Event": {"discriminator": {"propertyName": "version"}}}`,
+			`{"Event": {"discriminator": {"propertyName": "kind"}}}`,
+		)
+		Expect(kinds(changes)).To(Equal([]ChangeKind{DiscriminatorChanged}))
+		Expect(changes[0].Breaking).To(BeTrue())
+	})
+
+	It("should compare components of OpenAPI specs", func() {
+		changes, err := DiffOpenAPI(
+			`{"components": {"schemas": {"User": {"type": "object"}}}}`,
+			`{"components": {"schemas": {"User": {"type": "string"}}}}`,
+		)
+		Expect(err).To(BeNil())
+		Expect(kinds(changes)).To(Equal([]ChangeKind{TypeChanged}))
+
+		changes, err = DiffOpenAPIWithDirection(
+			`{"components": {"schemas": {"User": {"type": "object"}}}}`,
+			`{"components": {"schemas": {"User": {"type": "object", "nullable": true}}}}`,
+			Requests,
+		)
+		Expect(err).To(BeNil())
+		Expect(kinds(changes)).To(Equal([]ChangeKind{NullableAdded}))
+		Expect(HasBreaking(changes)).To(BeFalse())
+
+		_, err = DiffOpenAPI(`{}`, `{}`)
+		Expect(err).NotTo(BeNil())
+	})
+
+	It("should read components of specs and schemas like Convert", func() {
+		read := func(input string, opts Options) (map[string]interface{}, error) {
+			return ReadComponents(context.Background(), strings.NewReader(input), opts)
+		}
+		components, err := read(`{"components": {"schemas": {"ID": {"minimum": 9007199254740993}}}}`, Options{})
+		Expect(err).To(BeNil())
+		Expect(components).To(Equal(map[string]interface{}{
+			"ID": map[string]interface{}{"minimum": json.Number("9007199254740993")},
+		}))
+
+		components, err = read(`{"definitions": {"ID": {"exclusiveMinimum": 0}}}`, Options{})
+		Expect(err).To(BeNil())
+		Expect(components).To(Equal(map[string]interface{}{
+			"ID": map[string]interface{}{"minimum": json.Number("0"), "exclusiveMinimum": true},
+		}))
+
+		_, err = read(`{"definitions": {}} {}`, Options{})
+		Expect(err).To(MatchError(HaveSuffix("Not able to parse OpenAPI spec or JSON schema")))
+		_, err = read(`{"definitions": {"ID": {"type": "string"}}}`, Options{MaxInputSize: 10})
+		Expect(err).To(Equal(&LimitError{Input: "OpenAPI spec or JSON schema", Message: "is larger than 10 bytes"}))
+		_, err = read(`{"definitions": {"ID": {"type": "string"}}}`, Options{MaxDepth: 2})
+		Expect(err).To(BeAssignableToTypeOf(&LimitError{}))
+		_, err = read(`{"paths": {}}`, Options{})
+		Expect(err).NotTo(BeNil())
+	})
+
+	It("should be serialized for CI", func() {
+		res, err := json.Marshal(Change{
+			Pointer: "#/components/schemas/User", Kind: ComponentRemoved, Breaking: true, Message: "component User is removed",
+		})
+		Expect(err).To(BeNil())
+		Expect(res).To(MatchJSON(`{
+			"pointer": "#/components/schemas/User",
+			"kind": "component-removed",
+			"breaking": true,
+			"message": "component User is removed"
+		}`))
+	})
+})
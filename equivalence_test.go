@@ -0,0 +1,106 @@
+package jsonschema2openapi
+
+import (
+	"encoding/json"
+
+	"github.com/bunyk/jsonschema2openapi/validator"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+// expectFaithful checks that every translated definition accepts and rejects the same instances as the source one
+func expectFaithful(definitionsJSON string) {
+	var definitions map[string]interface{}
+	Expect(json.Unmarshal([]byte(definitionsJSON), &definitions)).To(Succeed())
+
+	translatedJSON, err := json.Marshal(TranslateDefinitions(definitions))
+	Expect(err).To(BeNil())
+	var translated map[string]interface{}
+	Expect(json.Unmarshal(translatedJSON, &translated)).To(Succeed())
+
+	source := validator.New(map[string]interface{}{"definitions": definitions}, validator.Draft07)
+	target := validator.New(map[string]interface{}{
+		"components": map[string]interface{}{"schemas": translated},
+	}, validator.OpenAPI30)
+	for name := range definitions {
+		mismatches := validator.Compare(
+			source, definitionsPrefix+escapeRefToken(name),
+			target, componentsPrefix+escapeRefToken(name),
+			nil,
+		)
+		Expect(mismatches).To(BeEmpty(), "translation of %s is not faithful", name)
+	}
+}
+
+var _ = Describe("Translation", func() {
+	It("should keep meaning of nullable types", func() {
+		expectFaithful(`{
+			"Name": { "oneOf": [ { "type": "string" }, { "type": "null" } ] },
+			"User": {
+				"type": "object",
+				"properties": { "name": { "$ref": "#/definitions/Name" } },
+				"required": ["name"]
+			}
+		}`)
+	})
+
+	It("should keep meaning of conditions", func() {
+		expectFaithful(`{
+			"Payment": {
+				"type": "object",
+				"properties": { "method": { "enum": ["card", "cash"] } },
+				"if": { "properties": { "method": { "enum": ["card"] } } },
+				"then": { "required": ["number"] },
+				"else": { "properties": { "number": false } }
+			}
+		}`)
+	})
+
+	It("should keep meaning of discriminated cases", func() {
+		expectFaithful(`{
+			"V1": {
+				"type": "object",
+				"properties": { "version": { "enum": ["v1"] }, "name": { "type": "string" } },
+				"required": ["version", "name"]
+			},
+			"V2": {
+				"type": "object",
+				"properties": { "version": { "enum": ["v2"] }, "title": { "type": "string" } },
+				"required": ["version", "title"]
+			},
+			"Event": {
+				"oneOf": [
+					{
+						"if": { "properties": { "version": { "enum": [ "v1" ] } } },
+						"then": { "$ref": "#/definitions/V1" },
+						"else": { "properties": { "version": { "enum": [ "v1" ] } } }
+					},
+					{
+						"if": { "properties": { "version": { "enum": [ "v2" ] } } },
+						"then": { "$ref": "#/definitions/V2" },
+						"else": { "properties": { "version": { "enum": [ "v2" ] } } }
+					}
+				]
+			}
+		}`)
+	})
+
+	It("should keep meaning of dependencies, bounds and boolean schemas", func() {
+		expectFaithful(`{
+			"Card": {
+				"type": "object",
+				"properties": {
+					"number": { "type": "string", "minLength": 3 },
+					"cvv": { "type": "integer", "exclusiveMinimum": 99, "maximum": 999 },
+					"legacy": false
+				},
+				"dependencies": {
+					"number": ["cvv"],
+					"token": { "required": ["provider"] }
+				},
+				"additionalProperties": true
+			}
+		}`)
+	})
+})
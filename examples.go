@@ -0,0 +1,89 @@
+package jsonschema2openapi
+
+import (
+	"sort"
+	"strconv"
+)
+
+// convertExamples replaces JSON Schema "examples" array with OpenAPI "example",
+// keeping examples that did not fit into it in "x-examples"
+func convertExamples(schema map[string]interface{}) map[string]interface{} {
+	examples, ok := schema["examples"].([]interface{})
+	if !ok {
+		return schema
+	}
+	delete(schema, "examples")
+	if len(examples) == 0 {
+		return schema
+	}
+	if _, ok := schema["example"]; !ok {
+		schema["example"] = examples[0]
+		examples = examples[1:]
+	}
+	if len(examples) > 0 {
+		schema["x-examples"] = examples
+	}
+	return schema
+}
+
+// namedExamples moves "examples" of every component to Example Objects named after component,
+// which are to be put into components/examples. First of them also becomes "example" of component.
+func namedExamples(components map[string]interface{}) (map[string]interface{}, map[string]interface{}) {
+	res := make(map[string]interface{})
+	named := make(map[string]interface{})
+	for name, c := range components {
+		schema, ok := c.(map[string]interface{})
+		if !ok {
+			res[name] = c
+			continue
+		}
+		examples, ok := schema["examples"].([]interface{})
+		if !ok {
+			res[name] = c
+			continue
+		}
+		copied := make(map[string]interface{})
+		for k, v := range schema {
+			if k != "examples" {
+				copied[k] = v
+			}
+		}
+		for i, example := range examples {
+			if i == 0 {
+				if _, ok := copied["example"]; !ok {
+					copied["example"] = example
+				}
+			}
+			named[name+"_"+strconv.Itoa(i+1)] = map[string]interface{}{
+				"value": example,
+			}
+		}
+		res[name] = copied
+	}
+	return res, named
+}
+
+// putExamples adds named examples to components/examples of template. Example which name is already used
+// gets suffix, like names of definitions which collide after sanitizing.
+func (t *translation) putExamples(examples map[string]interface{}) {
+	names := make([]string, 0, len(t.examples))
+	for name := range t.examples {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		candidate := name
+		for i := 2; hasKey(examples, candidate); i++ {
+			candidate = name + "_" + strconv.Itoa(i)
+		}
+		if candidate != name {
+			t.report("#/components/examples/"+escapeRefToken(name), "renamed to %s, as template already has example with this name", candidate)
+		}
+		examples[candidate] = t.examples[name]
+	}
+}
+
+func hasKey(m map[string]interface{}, key string) bool {
+	_, ok := m[key]
+	return ok
+}
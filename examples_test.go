@@ -0,0 +1,91 @@
+package jsonschema2openapi
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Examples", func() {
+	It("should become example and x-examples", func() {
+		res := TranslateDefinitions(map[string]interface{}{
+			"Name": map[string]interface{}{
+				"type":     "string",
+				"examples": []interface{}{"Alice", "Bob"},
+			},
+			"User": map[string]interface{}{
+				"properties": map[string]interface{}{
+					"age": map[string]interface{}{"examples": []interface{}{42.0}},
+				},
+			},
+		})
+		Expect(res).To(Equal(map[string]interface{}{
+			"Name": map[string]interface{}{
+				"type":       "string",
+				"example":    "Alice",
+				"x-examples": []interface{}{"Bob"},
+			},
+			"User": map[string]interface{}{
+				"properties": map[string]interface{}{
+					"age": map[string]interface{}{"example": 42.0},
+				},
+			},
+		}))
+	})
+
+	It("should be put into components/examples when asked", func() {
+		api, err := PutSchemaIntoOpenAPIWithOptions(`{
+			"definitions": {
+				"Name": { "type": "string", "examples": ["Alice", "Bob"] }
+			}
+		}`, minOpenAPI, Options{NamedExamples: true})
+		Expect(err).To(BeNil())
+
+		jq := Jq(api)
+		Expect(jq.String("components", "schemas", "Name", "example")).To(Equal("Alice"))
+		_, err = jq.Array("components", "schemas", "Name", "x-examples")
+		Expect(err).NotTo(BeNil())
+		Expect(jq.String("components", "examples", "Name_1", "value")).To(Equal("Alice"))
+		Expect(jq.String("components", "examples", "Name_2", "value")).To(Equal("Bob"))
+	})
+
+	It("should not overwrite examples of template", func() {
+		var diagnostics []string
+		api, err := PutSchemaIntoOpenAPIWithOptions(`{
+			"definitions": {
+				"Name": { "type": "string", "examples": ["Alice", "Bob"] }
+			}
+		}`, `{
+			"components": {
+				"schemas": {},
+				"examples": { "Name_1": { "value": "Carol" } }
+			}
+		}`, Options{
+			NamedExamples: true,
+			OnDiagnostic: func(d Diagnostic) {
+				diagnostics = append(diagnostics, d.String())
+			},
+		})
+		Expect(err).To(BeNil())
+
+		jq := Jq(api)
+		Expect(jq.String("components", "examples", "Name_1", "value")).To(Equal("Carol"))
+		Expect(jq.String("components", "examples", "Name_1_2", "value")).To(Equal("Alice"))
+		Expect(jq.String("components", "examples", "Name_2", "value")).To(Equal("Bob"))
+		Expect(diagnostics).To(Equal([]string{
+			"#/components/examples/Name_1: renamed to Name_1_2, as template already has example with this name",
+		}))
+	})
+
+	It("should stay in x-examples of translated definitions, which have no components/examples", func() {
+		res, err := TranslateDefinitionsWithOptions(map[string]interface{}{
+			"Name": map[string]interface{}{"examples": []interface{}{"x", "y"}},
+		}, Options{NamedExamples: true})
+		Expect(err).To(BeNil())
+		Expect(res).To(Equal(map[string]interface{}{
+			"Name": map[string]interface{}{
+				"example":    "x",
+				"x-examples": []interface{}{"y"},
+			},
+		}))
+	})
+})
@@ -0,0 +1,111 @@
+package jsonschema2openapi
+
+import (
+	"encoding/json"
+	"math/big"
+)
+
+// Pairs of inclusive and exclusive bound keywords, and whether bound is lower
+var exclusiveBounds = []struct {
+	inclusive, exclusive string
+	lower                bool
+}{
+	{"minimum", "exclusiveMinimum", true},
+	{"maximum", "exclusiveMaximum", false},
+}
+
+// ExclusiveBoundsToBoolean recursively converts draft-06 numeric bounds like "exclusiveMinimum": 5
+// to boolean form of OpenAPI 3.0 and draft-04: "minimum": 5, "exclusiveMinimum": true.
+// When both inclusive and exclusive bounds are given, the tighter one is kept. Bounds decoded as json.Number
+// are compared exactly, however large or long they are.
+func ExclusiveBoundsToBoolean(jsonData interface{}) interface{} {
+	return mapSchemas(jsonData, booleanExclusiveBounds)
+}
+
+// ExclusiveBoundsToNumeric recursively converts boolean bounds like "minimum": 5, "exclusiveMinimum": true
+// to numeric form of draft-06 and OpenAPI 3.1: "exclusiveMinimum": 5. Bounds are moved as they are,
+// so json.Number keeps its exact text.
+func ExclusiveBoundsToNumeric(jsonData interface{}) interface{} {
+	return mapSchemas(jsonData, numericExclusiveBounds)
+}
+
+func booleanExclusiveBounds(schema map[string]interface{}) map[string]interface{} {
+	for _, b := range exclusiveBounds {
+		if _, ok := toRat(schema[b.exclusive]); !ok {
+			continue
+		}
+		cmp, ok := compareNumbers(schema[b.exclusive], schema[b.inclusive])
+		tighter := !ok || (b.lower && cmp >= 0) || (!b.lower && cmp <= 0)
+		if tighter {
+			schema[b.inclusive] = schema[b.exclusive]
+			schema[b.exclusive] = true
+		} else {
+			delete(schema, b.exclusive)
+		}
+	}
+	return schema
+}
+
+func numericExclusiveBounds(schema map[string]interface{}) map[string]interface{} {
+	for _, b := range exclusiveBounds {
+		exclusive, ok := schema[b.exclusive].(bool)
+		if !ok {
+			continue
+		}
+		if bound, ok := schema[b.inclusive]; ok && exclusive {
+			schema[b.exclusive] = bound
+			delete(schema, b.inclusive)
+		} else {
+			delete(schema, b.exclusive)
+		}
+	}
+	return schema
+}
+
+// toFloat returns value of JSON number
+func toFloat(jsonData interface{}) (float64, bool) {
+	switch v := jsonData.(type) {
+	case float64:
+		return v, true
+	case json.Number:
+		f, err := v.Float64()
+		return f, err == nil
+	case int:
+		return float64(v), true
+	default:
+		return 0, false
+	}
+}
+
+// toRat returns exact value of JSON number. Text of json.Number is parsed as it is,
+// so large integers and long decimals are not rounded like they would be by toFloat.
+func toRat(jsonData interface{}) (*big.Rat, bool) {
+	switch v := jsonData.(type) {
+	case float64:
+		r := new(big.Rat)
+		if r.SetFloat64(v) == nil {
+			return nil, false
+		}
+		return r, true
+	case json.Number:
+		return new(big.Rat).SetString(string(v))
+	case int:
+		return new(big.Rat).SetInt64(int64(v)), true
+	default:
+		return nil, false
+	}
+}
+
+// compareNumbers compares JSON numbers a and b exactly, returning -1, 0 or +1 like big.Rat.Cmp.
+// It returns false when any of them is not a number.
+func compareNumbers(a, b interface{}) (int, bool) {
+	x, ok := toRat(a)
+	if !ok {
+		return 0, false
+	}
+	y, ok := toRat(b)
+	if !ok {
+		return 0, false
+	}
+	return x.Cmp(y), true
+}
@@ -0,0 +1,69 @@
+package jsonschema2openapi
+
+import (
+	"encoding/json"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Exclusive bounds", func() {
+	It("should convert numeric bounds to boolean", func() {
+		res := TranslateDefinitions(map[string]interface{}{
+			"Positive": map[string]interface{}{"type": "number", "exclusiveMinimum": 0.0},
+			"Percent": map[string]interface{}{
+				"type":             "number",
+				"minimum":          1.0,
+				"exclusiveMinimum": 0.0,
+				"maximum":          100.0,
+				"exclusiveMaximum": 50.0,
+			},
+		})
+		Expect(res).To(Equal(map[string]interface{}{
+			"Positive": map[string]interface{}{"type": "number", "minimum": 0.0, "exclusiveMinimum": true},
+			"Percent": map[string]interface{}{
+				"type":             "number",
+				"minimum":          1.0,
+				"maximum":          50.0,
+				"exclusiveMaximum": true,
+			},
+		}))
+	})
+
+	It("should compare bounds exactly", func() {
+		res := TranslateDefinitions(map[string]interface{}{
+			"Lower": map[string]interface{}{
+				"minimum":          json.Number("9007199254740993"),
+				"exclusiveMinimum": json.Number("9007199254740992"),
+			},
+			"Upper": map[string]interface{}{
+				"maximum":          json.Number("0.30000000000000001"),
+				"exclusiveMaximum": json.Number("0.3"),
+			},
+		})
+		Expect(res).To(Equal(map[string]interface{}{
+			"Lower": map[string]interface{}{"minimum": json.Number("9007199254740993")},
+			"Upper": map[string]interface{}{"maximum": json.Number("0.3"), "exclusiveMaximum": true},
+		}))
+	})
+
+	It("should convert boolean bounds back to numeric", func() {
+		res := ExclusiveBoundsToNumeric(map[string]interface{}{
+			"properties": map[string]interface{}{
+				"a": map[string]interface{}{"minimum": 0.0, "exclusiveMinimum": true},
+				"b": map[string]interface{}{"maximum": 10.0, "exclusiveMaximum": false},
+			},
+		})
+		Expect(res).To(Equal(map[string]interface{}{
+			"properties": map[string]interface{}{
+				"a": map[string]interface{}{"exclusiveMinimum": 0.0},
+				"b": map[string]interface{}{"maximum": 10.0},
+			},
+		}))
+	})
+
+	It("should round trip", func() {
+		schema := map[string]interface{}{"exclusiveMaximum": 3.0}
+		Expect(ExclusiveBoundsToNumeric(ExclusiveBoundsToBoolean(schema))).To(Equal(schema))
+	})
+})
@@ -0,0 +1,45 @@
+package jsonschema2openapi
+
+// Formats of OpenAPI for values of JSON Schema "contentEncoding"
+var encodingFormats = map[string]string{
+	"base64": "byte",
+	"binary": "binary",
+}
+
+// formats replaces "contentEncoding" with OpenAPI "format", moves "contentMediaType" to "x-contentMediaType",
+// and translates "format" using Options.Formats and Options.FormatFunc
+func (t *translation) formats(schema map[string]interface{}) map[string]interface{} {
+	if format, ok := schema["format"].(string); ok {
+		if newFormat, ok := t.opts.Formats[format]; ok {
+			format = newFormat
+		}
+		if t.opts.FormatFunc != nil {
+			format = t.opts.FormatFunc(format)
+		}
+		if format == "" {
+			delete(schema, "format")
+		} else {
+			schema["format"] = format
+		}
+	}
+	if encoding, ok := schema["contentEncoding"]; ok {
+		delete(schema, "contentEncoding")
+		format, known := encodingFormats[toString(encoding)]
+		if _, hasFormat := schema["format"]; known && !hasFormat {
+			schema["format"] = format
+		} else {
+			schema["x-contentEncoding"] = encoding
+		}
+	}
+	if mediaType, ok := schema["contentMediaType"]; ok {
+		delete(schema, "contentMediaType")
+		schema["x-contentMediaType"] = mediaType
+	}
+	return schema
+}
+
+// toString returns value of JSON string, or empty string for other values
+func toString(jsonData interface{}) string {
+	s, _ := jsonData.(string)
+	return s
+}
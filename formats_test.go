@@ -0,0 +1,52 @@
+package jsonschema2openapi
+
+import (
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Formats", func() {
+	It("should translate content encoding and media type", func() {
+		res := TranslateDefinitions(map[string]interface{}{
+			"Image": map[string]interface{}{
+				"type":             "string",
+				"contentEncoding":  "base64",
+				"contentMediaType": "image/png",
+			},
+			"Mail": map[string]interface{}{
+				"type":            "string",
+				"contentEncoding": "quoted-printable",
+			},
+		})
+		Expect(res).To(Equal(map[string]interface{}{
+			"Image": map[string]interface{}{
+				"type":               "string",
+				"format":             "byte",
+				"x-contentMediaType": "image/png",
+			},
+			"Mail": map[string]interface{}{
+				"type":              "string",
+				"x-contentEncoding": "quoted-printable",
+			},
+		}))
+	})
+
+	It("should translate formats with table and function", func() {
+		res, err := TranslateDefinitionsWithOptions(map[string]interface{}{
+			"Timestamp": map[string]interface{}{"type": "string", "format": "datetime"},
+			"Host":      map[string]interface{}{"type": "string", "format": "hostname"},
+			"ID":        map[string]interface{}{"type": "string", "format": "UUID"},
+		}, Options{
+			Formats:    map[string]string{"datetime": "date-time", "hostname": ""},
+			FormatFunc: strings.ToLower,
+		})
+		Expect(err).To(BeNil())
+		Expect(res).To(Equal(map[string]interface{}{
+			"Timestamp": map[string]interface{}{"type": "string", "format": "date-time"},
+			"Host":      map[string]interface{}{"type": "string"},
+			"ID":        map[string]interface{}{"type": "string", "format": "uuid"},
+		}))
+	})
+})
@@ -0,0 +1,33 @@
+package jsonschema2openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+)
+
+// decodeJSON is json.Unmarshal which keeps numbers exact as json.Number
+func decodeJSON(data []byte, v interface{}) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err := dec.Decode(v); err != nil {
+		return err
+	}
+	// like json.Unmarshal, reject anything except whitespace after value
+	if err := dec.Decode(&struct{}{}); err != io.EOF {
+		return errors.New("invalid data after top-level value")
+	}
+	return nil
+}
+
+// encodeJSON is json.Marshal which does not escape <, > and &
+func encodeJSON(v interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), nil
+}
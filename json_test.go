@@ -0,0 +1,96 @@
+package jsonschema2openapi
+
+import (
+	"encoding/json"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("PutSchemaIntoOpenAPI numbers and strings", func() {
+	It("should keep large integers exact", func() {
+		api, err := PutSchemaIntoOpenAPI(`{
+			"definitions": {
+				"ID": {
+					"type": "integer",
+					"exclusiveMaximum": 9223372036854775807,
+					"default": 9007199254740993,
+					"enum": [9007199254740993, 9007199254740995, 1.10]
+				}
+			}
+		}`, minOpenAPI)
+		Expect(err).To(BeNil())
+		Expect(api).To(ContainSubstring(`"maximum": 9223372036854775807`))
+		Expect(api).To(ContainSubstring(`"default": 9007199254740993`))
+		Expect(api).To(ContainSubstring("9007199254740995,\n"))
+		Expect(api).To(ContainSubstring("1.10\n"))
+	})
+
+	It("should compare large exclusive bounds exactly", func() {
+		api, err := PutSchemaIntoOpenAPI(`{
+			"definitions": {
+				"ID": {
+					"type": "integer",
+					"minimum": 9007199254740993,
+					"exclusiveMinimum": 9007199254740992,
+					"maximum": 9223372036854775806,
+					"exclusiveMaximum": 9223372036854775807
+				}
+			}
+		}`, minOpenAPI)
+		Expect(err).To(BeNil())
+		Expect(api).To(ContainSubstring(`"minimum": 9007199254740993`))
+		Expect(api).NotTo(ContainSubstring(`"exclusiveMinimum"`))
+		Expect(api).To(ContainSubstring(`"maximum": 9223372036854775806`))
+		Expect(api).NotTo(ContainSubstring(`"exclusiveMaximum"`))
+
+		var schema interface{}
+		Expect(decodeJSON([]byte(`{"minimum": 9007199254740993, "exclusiveMinimum": 9007199254740992}`), &schema)).To(Succeed())
+		Expect(ExclusiveBoundsToBoolean(schema)).To(Equal(map[string]interface{}{
+			"minimum": json.Number("9007199254740993"),
+		}))
+		Expect(decodeJSON([]byte(`{"minimum": 9007199254740993, "exclusiveMinimum": 9007199254740994}`), &schema)).To(Succeed())
+		Expect(ExclusiveBoundsToNumeric(ExclusiveBoundsToBoolean(schema))).To(Equal(map[string]interface{}{
+			"exclusiveMinimum": json.Number("9007199254740994"),
+		}))
+	})
+
+	It("should not escape HTML characters", func() {
+		api, err := PutSchemaIntoOpenAPI(`{
+			"definitions": {
+				"Tag": {
+					"type": "string",
+					"pattern": "^<[a-z]+>$|^&\\w+;$",
+					"description": "Use <b>bold</b> & \"quotes\"\n* markdown"
+				}
+			}
+		}`, minOpenAPI)
+		Expect(err).To(BeNil())
+		Expect(api).To(ContainSubstring(`"pattern": "^<[a-z]+>$|^&\\w+;$"`))
+		Expect(api).To(ContainSubstring(`"description": "Use <b>bold</b> & \"quotes\"\n* markdown"`))
+	})
+
+	It("should reject data after JSON value", func() {
+		_, err := PutSchemaIntoOpenAPI(`{"definitions": {}} trailing garbage`, minOpenAPI)
+		Expect(err).To(MatchError(HaveSuffix(". Was not able to parse JSON schema")))
+		_, err = PutSchemaIntoOpenAPI(`{"definitions": {}}`, minOpenAPI+`}`)
+		Expect(err).NotTo(BeNil())
+		_, err = ExtractSchemaFromOpenAPI(minOpenAPI + ` {}`)
+		Expect(err).NotTo(BeNil())
+		_, err = PutSchemaIntoOpenAPI("{\"definitions\": {}}\n\t ", minOpenAPI)
+		Expect(err).To(BeNil())
+	})
+})
+
+var _ = Describe("TranslateSchemas numbers", func() {
+	It("should keep large integers exact", func() {
+		var definitions map[string]*JSONSchema
+		Expect(json.Unmarshal([]byte(`{
+			"ID": {"minimum": 9007199254740993, "enum": [9007199254740993]}
+		}`), &definitions)).To(Succeed())
+		components, err := TranslateSchemas(definitions, Options{})
+		Expect(err).To(BeNil())
+		Expect(components["ID"].Minimum).To(Equal(json.Number("9007199254740993")))
+		Expect(components["ID"].Enum).To(Equal([]interface{}{json.Number("9007199254740993")}))
+	})
+})
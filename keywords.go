@@ -0,0 +1,93 @@
+package jsonschema2openapi
+
+import (
+	"fmt"
+	"sort"
+)
+
+// KeywordPolicy tells what to do with keywords unknown to OpenAPI
+type KeywordPolicy int
+
+const (
+	// KeepUnknown leaves unknown keywords as they are
+	KeepUnknown KeywordPolicy = iota
+	// PrefixUnknown turns unknown keywords into extensions by adding "x-" prefix
+	PrefixUnknown
+	// DropUnknown removes unknown keywords
+	DropUnknown
+	// RejectUnknown fails translation when there are unknown keywords
+	RejectUnknown
+)
+
+// Fields of OpenAPI 3.0 Schema Object
+var openAPIKeywords = map[string]bool{
+	"$ref":                 true,
+	"title":                true,
+	"multipleOf":           true,
+	"maximum":              true,
+	"exclusiveMaximum":     true,
+	"minimum":              true,
+	"exclusiveMinimum":     true,
+	"maxLength":            true,
+	"minLength":            true,
+	"pattern":              true,
+	"maxItems":             true,
+	"minItems":             true,
+	"uniqueItems":          true,
+	"maxProperties":        true,
+	"minProperties":        true,
+	"required":             true,
+	"enum":                 true,
+	"type":                 true,
+	"allOf":                true,
+	"oneOf":                true,
+	"anyOf":                true,
+	"not":                  true,
+	"items":                true,
+	"properties":           true,
+	"additionalProperties": true,
+	"description":          true,
+	"format":               true,
+	"default":              true,
+	"nullable":             true,
+	"discriminator":        true,
+	"readOnly":             true,
+	"writeOnly":            true,
+	"xml":                  true,
+	"externalDocs":         true,
+	"example":              true,
+	"deprecated":           true,
+}
+
+// keywords renames keywords of schema according to Options.Keywords, and handles unknown ones
+// according to Options.UnknownKeywords
+func (t *translation) keywords(pointer string, schema map[string]interface{}) map[string]interface{} {
+	keys := make([]string, 0, len(schema))
+	for k := range schema {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		v := schema[k]
+		if newKey, ok := t.opts.Keywords[k]; ok {
+			delete(schema, k)
+			if newKey != "" {
+				schema[newKey] = v
+			}
+			continue
+		}
+		if openAPIKeywords[k] || isExtension(k) {
+			continue
+		}
+		switch t.opts.UnknownKeywords {
+		case PrefixUnknown:
+			delete(schema, k)
+			schema["x-"+k] = v
+		case DropUnknown:
+			delete(schema, k)
+		case RejectUnknown:
+			t.errors = append(t.errors, fmt.Sprintf("unknown keyword %q at %s", k, pointer))
+		}
+	}
+	return schema
+}
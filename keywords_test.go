@@ -0,0 +1,120 @@
+package jsonschema2openapi
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Keywords", func() {
+	definitions := func() map[string]interface{} {
+		return map[string]interface{}{
+			"Money": map[string]interface{}{
+				"type":            "string",
+				"goType":          "decimal.Decimal",
+				"deprecatedSince": "v2",
+				"properties": map[string]interface{}{
+					"goType": map[string]interface{}{"tsType": "string"},
+				},
+			},
+		}
+	}
+
+	It("should be kept by default", func() {
+		Expect(TranslateDefinitions(definitions())).To(Equal(definitions()))
+	})
+
+	It("should be mapped and prefixed", func() {
+		res, err := TranslateDefinitionsWithOptions(definitions(), Options{
+			Keywords:        map[string]string{"goType": "x-go-type", "tsType": ""},
+			UnknownKeywords: PrefixUnknown,
+		})
+		Expect(err).To(BeNil())
+		Expect(res).To(Equal(map[string]interface{}{
+			"Money": map[string]interface{}{
+				"type":              "string",
+				"x-go-type":         "decimal.Decimal",
+				"x-deprecatedSince": "v2",
+				"properties": map[string]interface{}{
+					"goType": map[string]interface{}{},
+				},
+			},
+		}))
+	})
+
+	It("should be dropped", func() {
+		res, err := TranslateDefinitionsWithOptions(definitions(), Options{UnknownKeywords: DropUnknown})
+		Expect(err).To(BeNil())
+		Expect(res).To(HaveKeyWithValue("Money", map[string]interface{}{
+			"type": "string",
+			"properties": map[string]interface{}{
+				"goType": map[string]interface{}{},
+			},
+		}))
+	})
+
+	It("should be rejected", func() {
+		_, err := TranslateDefinitionsWithOptions(definitions(), Options{UnknownKeywords: RejectUnknown})
+		Expect(err).NotTo(BeNil())
+		Expect(err.Error()).To(ContainSubstring(`unknown keyword "tsType" at #/components/schemas/Money/properties/goType`))
+		Expect(err.Error()).To(ContainSubstring(`unknown keyword "goType" at #/components/schemas/Money;`))
+	})
+
+	Describe("values", func() {
+		// value of custom keyword which looks like schema
+		tags := func() map[string]interface{} {
+			return map[string]interface{}{
+				"json":     "name,omitempty",
+				"$comment": "tags of field",
+				"examples": []interface{}{"a"},
+				"oneOf":    []interface{}{map[string]interface{}{"type": "null"}, map[string]interface{}{"type": "string"}},
+			}
+		}
+		schema := func() map[string]interface{} {
+			return map[string]interface{}{
+				"A": map[string]interface{}{"type": "string", "goTags": tags()},
+			}
+		}
+
+		It("should be copied when keyword is mapped", func() {
+			for _, policy := range []KeywordPolicy{KeepUnknown, PrefixUnknown, DropUnknown, RejectUnknown} {
+				res, err := TranslateDefinitionsWithOptions(schema(), Options{
+					Keywords:        map[string]string{"goTags": "x-go-tags"},
+					UnknownKeywords: policy,
+				})
+				Expect(err).To(BeNil())
+				Expect(res).To(Equal(map[string]interface{}{
+					"A": map[string]interface{}{"type": "string", "x-go-tags": tags()},
+				}))
+			}
+		})
+
+		It("should be copied when keyword is kept", func() {
+			res, err := TranslateDefinitionsWithOptions(schema(), Options{UnknownKeywords: KeepUnknown})
+			Expect(err).To(BeNil())
+			Expect(res).To(Equal(schema()))
+		})
+
+		It("should be copied when keyword is prefixed", func() {
+			res, err := TranslateDefinitionsWithOptions(schema(), Options{UnknownKeywords: PrefixUnknown})
+			Expect(err).To(BeNil())
+			Expect(res).To(Equal(map[string]interface{}{
+				"A": map[string]interface{}{"type": "string", "x-goTags": tags()},
+			}))
+		})
+
+		It("should be dropped together with keyword", func() {
+			res, err := TranslateDefinitionsWithOptions(schema(), Options{UnknownKeywords: DropUnknown})
+			Expect(err).To(BeNil())
+			Expect(res).To(Equal(map[string]interface{}{
+				"A": map[string]interface{}{"type": "string"},
+			}))
+		})
+
+		It("should not be rejected, only keyword is", func() {
+			_, err := TranslateDefinitionsWithOptions(schema(), Options{UnknownKeywords: RejectUnknown})
+			Expect(err).NotTo(BeNil())
+			Expect(err.Error()).To(ContainSubstring(`unknown keyword "goTags" at #/components/schemas/A`))
+			Expect(err.Error()).NotTo(ContainSubstring(`#/components/schemas/A/goTags`))
+		})
+	})
+})
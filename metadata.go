@@ -0,0 +1,169 @@
+package jsonschema2openapi
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Keywords of JSON Schema which are not allowed by OpenAPI and just dropped
+var metadataKeywords = map[string]bool{
+	"$schema":     true,
+	"$id":         true,
+	"$anchor":     true,
+	"$vocabulary": true,
+}
+
+// cleanupMetadata removes JSON Schema dialect metadata from schema, and replaces "$comment" with "x-comment"
+func cleanupMetadata(schema map[string]interface{}) map[string]interface{} {
+	for k := range metadataKeywords {
+		delete(schema, k)
+	}
+	if comment, ok := schema["$comment"]; ok {
+		schema["x-comment"] = comment
+		delete(schema, "$comment")
+	}
+	return schema
+}
+
+// hoistDefinitions moves "definitions" nested anywhere inside of definitions to top level,
+// and rewrites references to them. Hoisted definition keeps its own name if it is free,
+// otherwise it is prefixed with name of definition where it was found.
+// References start with prefix. Also returns new names of hoisted definitions by their pointers relative to definitions.
+func hoistDefinitions(definitions map[string]interface{}, prefix string) (map[string]interface{}, map[string]string) {
+	names := make([]string, 0, len(definitions))
+	for k := range definitions {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	taken := make(map[string]bool)
+	for _, name := range names {
+		taken[name] = true
+	}
+
+	h := hoister{
+		prefix: prefix,
+		res:    make(map[string]interface{}),
+		taken:  taken,
+		moved:  make(map[string]string),
+	}
+	for _, name := range names {
+		h.res[name] = h.hoist(definitions[name], name, escapeRefToken(name))
+	}
+	if len(h.moved) == 0 {
+		return definitions, h.moved
+	}
+	res := make(map[string]interface{}, len(h.res))
+	for name, schema := range h.res {
+		res[name] = mapRefs(schema, true, h.rewriteRef)
+	}
+	return res, h.moved
+}
+
+type hoister struct {
+	prefix string
+	res    map[string]interface{}
+	taken  map[string]bool
+	// moved maps JSON pointers of hoisted definitions (relative to definitions) to their new names
+	moved map[string]string
+}
+
+// hoist returns schema without nested definitions, which are moved to h.res.
+// name is name of top level definition containing schema, and pointer is path to schema in original document.
+func (h *hoister) hoist(jsonData interface{}, name, pointer string) interface{} {
+	switch v := jsonData.(type) {
+	case map[string]interface{}:
+		res := make(map[string]interface{})
+		for k, value := range v {
+			p := pointer + "/" + escapeRefToken(k)
+			nested, ok := value.(map[string]interface{})
+			switch {
+			case k == "definitions" && ok:
+				keys := make([]string, 0, len(nested))
+				for key := range nested {
+					keys = append(keys, key)
+				}
+				sort.Strings(keys)
+				for _, key := range keys {
+					newName := h.name(name, key)
+					h.moved[p+"/"+escapeRefToken(key)] = newName
+					h.res[newName] = h.hoist(nested[key], newName, p+"/"+escapeRefToken(key))
+				}
+			case schemaMapKeywords[k] && ok:
+				schemas := make(map[string]interface{})
+				for key, schema := range nested {
+					schemas[key] = h.hoist(schema, name, p+"/"+escapeRefToken(key))
+				}
+				res[k] = schemas
+			case dataKeywords[k] || isExtension(k):
+				res[k] = value
+			default:
+				res[k] = h.hoist(value, name, p)
+			}
+		}
+		return res
+	case []interface{}:
+		res := make([]interface{}, 0, len(v))
+		for i, elem := range v {
+			res = append(res, h.hoist(elem, name, pointer+"/"+strconv.Itoa(i)))
+		}
+		return res
+	default:
+		return v
+	}
+}
+
+// name picks free name for definition hoisted from parent
+func (h *hoister) name(parent, name string) string {
+	candidate := name
+	if h.taken[candidate] {
+		candidate = parent + "." + name
+	}
+	base := candidate
+	for i := 2; h.taken[candidate]; i++ {
+		candidate = base + "_" + strconv.Itoa(i)
+	}
+	h.taken[candidate] = true
+	return candidate
+}
+
+// hasNestedDefinitions checks if there are "definitions" anywhere inside of definitions, without copying them
+func hasNestedDefinitions(jsonData interface{}) bool {
+	switch v := jsonData.(type) {
+	case map[string]interface{}:
+		for k, value := range v {
+			if _, ok := value.(map[string]interface{}); ok && k == "definitions" {
+				return true
+			}
+			if hasNestedDefinitions(value) {
+				return true
+			}
+		}
+	case []interface{}:
+		for _, elem := range v {
+			if hasNestedDefinitions(elem) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// rewriteRef points reference to hoisted definition, using the longest moved pointer that matches
+func (h *hoister) rewriteRef(ref string) string {
+	if !strings.HasPrefix(ref, h.prefix) {
+		return ref
+	}
+	pointer := ref[len(h.prefix):]
+	for p := pointer; p != ""; {
+		if newName, ok := h.moved[p]; ok {
+			return h.prefix + escapeRefToken(newName) + pointer[len(p):]
+		}
+		i := strings.LastIndex(p, "/")
+		if i < 0 {
+			break
+		}
+		p = p[:i]
+	}
+	return ref
+}
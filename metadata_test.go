@@ -0,0 +1,103 @@
+package jsonschema2openapi
+
+import (
+	"encoding/json"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Metadata cleanup", func() {
+	It("should remove dialect keywords and keep comments as extension", func() {
+		res := TranslateDefinitions(map[string]interface{}{
+			"User": map[string]interface{}{
+				"$schema":  "http://json-schema.org/draft-07/schema#",
+				"$id":      "https://example.com/user.json",
+				"$comment": "generated",
+				"properties": map[string]interface{}{
+					"$id": map[string]interface{}{"type": "string", "$comment": "database id"},
+				},
+				"default": map[string]interface{}{"$id": "data"},
+			},
+		})
+		Expect(res).To(Equal(map[string]interface{}{
+			"User": map[string]interface{}{
+				"x-comment": "generated",
+				"properties": map[string]interface{}{
+					"$id": map[string]interface{}{"type": "string", "x-comment": "database id"},
+				},
+				"default": map[string]interface{}{"$id": "data"},
+			},
+		}))
+	})
+
+	It("should keep metadata when asked", func() {
+		res, err := TranslateDefinitionsWithOptions(map[string]interface{}{
+			"User": map[string]interface{}{"$comment": "generated"},
+		}, Options{KeepMetadata: true})
+		Expect(err).To(BeNil())
+		Expect(res).To(HaveKeyWithValue("User", map[string]interface{}{"$comment": "generated"}))
+	})
+
+	It("should hoist nested definitions and rewrite references to them", func() {
+		var definitions map[string]interface{}
+		Expect(json.Unmarshal([]byte(`{
+			"Address": { "type": "string" },
+			"User": {
+				"properties": {
+					"address": { "$ref": "#/definitions/User/definitions/Address" },
+					"name": { "$ref": "#/definitions/User/definitions/Name/properties/first" }
+				},
+				"definitions": {
+					"Address": { "type": "object" },
+					"Name": {
+						"properties": { "first": { "$ref": "#/definitions/User/definitions/Name/definitions/First" } },
+						"definitions": { "First": { "type": "string" } }
+					}
+				}
+			}
+		}`), &definitions)).To(Succeed())
+
+		res, err := json.Marshal(TranslateDefinitions(definitions))
+		Expect(err).To(BeNil())
+		Expect(res).To(MatchJSON(`{
+			"Address": { "type": "string" },
+			"User": {
+				"properties": {
+					"address": { "$ref": "#/components/schemas/User.Address" },
+					"name": { "$ref": "#/components/schemas/Name/properties/first" }
+				}
+			},
+			"User.Address": { "type": "object" },
+			"Name": {
+				"properties": { "first": { "$ref": "#/components/schemas/First" } }
+			},
+			"First": { "type": "string" }
+		}`))
+	})
+
+	It("should not rewrite references inside data and extensions of hoisted definitions", func() {
+		var definitions map[string]interface{}
+		Expect(json.Unmarshal([]byte(`{
+			"User": {
+				"properties": {"$ref": { "$ref": "#/definitions/User/definitions/Address" }},
+				"default": { "$ref": "#/definitions/User/definitions/Address" },
+				"enum": [{ "$ref": "#/definitions/User/definitions/Address" }],
+				"x-source": { "$ref": "#/definitions/User/definitions/Address" },
+				"definitions": { "Address": { "type": "object" } }
+			}
+		}`), &definitions)).To(Succeed())
+
+		res, err := json.Marshal(TranslateDefinitions(definitions))
+		Expect(err).To(BeNil())
+		Expect(res).To(MatchJSON(`{
+			"User": {
+				"properties": {"$ref": { "$ref": "#/components/schemas/Address" }},
+				"default": { "$ref": "#/definitions/User/definitions/Address" },
+				"enum": [{ "$ref": "#/definitions/User/definitions/Address" }],
+				"x-source": { "$ref": "#/definitions/User/definitions/Address" }
+			},
+			"Address": { "type": "object" }
+		}`))
+	})
+})
@@ -0,0 +1,117 @@
+package jsonschema2openapi
+
+import (
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Characters allowed in keys of OpenAPI components are ^[a-zA-Z0-9\.\-_]+$
+var invalidNameChars = regexp.MustCompile(`[^a-zA-Z0-9\.\-_]`)
+
+// SanitizeName replaces every character which is not allowed in OpenAPI component name with replacement.
+// Invalid characters of replacement itself are dropped, and "_" is used when nothing is left of it.
+//
+//	SanitizeName("github.com/acme/api.User", "_") == "github.com_acme_api.User"
+//	SanitizeName("Map[string]int", "_") == "Map_string_int"
+func SanitizeName(name, replacement string) string {
+	replacement = invalidNameChars.ReplaceAllString(replacement, "")
+	if replacement == "" {
+		replacement = "_"
+	}
+	res := invalidNameChars.ReplaceAllString(name, replacement)
+	if res == "" {
+		return replacement
+	}
+	return res
+}
+
+// sanitizeNames returns mapping from old names to new ones, for names that need to change.
+// Names which don't change are never taken, for others suffix _2, _3... is added on collision.
+func sanitizeNames(definitions map[string]interface{}, opts Options) map[string]string {
+	names := make([]string, 0, len(definitions))
+	for k := range definitions {
+		names = append(names, k)
+	}
+	sort.Strings(names) // so disambiguation does not depend on map order
+
+	taken := make(map[string]bool)
+	wanted := make(map[string]string)
+	for _, name := range names {
+		newName := name
+		if opts.NameFunc != nil {
+			newName = opts.NameFunc(newName)
+		}
+		newName = SanitizeName(newName, opts.NameReplacement)
+		if newName == name {
+			taken[name] = true
+		} else {
+			wanted[name] = newName
+		}
+	}
+
+	renames := make(map[string]string)
+	for _, name := range names {
+		newName, ok := wanted[name]
+		if !ok {
+			continue
+		}
+		candidate := newName
+		for i := 2; taken[candidate]; i++ {
+			candidate = newName + "_" + strconv.Itoa(i)
+		}
+		taken[candidate] = true
+		renames[name] = candidate
+	}
+	return renames
+}
+
+// Recursively replace references to prefix + old name with references to prefix + new name.
+// References in examples, defaults, enums and extensions are kept, see mapRefs.
+func renameRefs(jsonData interface{}, prefix string, renames map[string]string) interface{} {
+	if len(renames) == 0 {
+		return jsonData
+	}
+	return mapRefs(jsonData, false, func(ref string) string {
+		return renameRef(ref, prefix, renames)
+	})
+}
+
+func renameRef(ref, prefix string, renames map[string]string) string {
+	name, rest, ok := splitRef(ref, prefix)
+	if !ok {
+		return ref
+	}
+	newName, ok := renames[name]
+	if !ok {
+		return ref
+	}
+	return prefix + escapeRefToken(newName) + rest
+}
+
+// splitRef splits reference like "#/definitions/a~1b/properties/c" with prefix "#/definitions/"
+// into unescaped name "a/b" and rest "/properties/c"
+func splitRef(ref, prefix string) (name, rest string, ok bool) {
+	if !strings.HasPrefix(ref, prefix) {
+		return "", "", false
+	}
+	name = ref[len(prefix):]
+	if i := strings.Index(name, "/"); i >= 0 {
+		name, rest = name[:i], name[i:]
+	}
+	if unescaped, err := url.PathUnescape(name); err == nil {
+		name = unescaped
+	}
+	return unescapeRefToken(name), rest, true
+}
+
+// JSON Pointer escaping, see https://tools.ietf.org/html/rfc6901#section-3
+func escapeRefToken(token string) string {
+	return strings.Replace(strings.Replace(token, "~", "~0", -1), "/", "~1", -1)
+}
+
+func unescapeRefToken(token string) string {
+	return strings.Replace(strings.Replace(token, "~1", "/", -1), "~0", "~", -1)
+}
@@ -0,0 +1,123 @@
+package jsonschema2openapi
+
+import (
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("SanitizeName", func() {
+	It("should replace characters not allowed in component names", func() {
+		Expect(SanitizeName("github.com/acme/api.User", "_")).To(Equal("github.com_acme_api.User"))
+		Expect(SanitizeName("Map[string]int", "-")).To(Equal("Map-string-int"))
+	})
+	It("should not use invalid replacement", func() {
+		Expect(SanitizeName("a b", "/")).To(Equal("a_b"))
+	})
+})
+
+var _ = Describe("Definition names", func() {
+	It("should rename definitions and references to them", func() {
+		api, err := PutSchemaIntoOpenAPI(`{
+			"definitions": {
+				"github.com/acme/api.User": {
+					"type": "object"
+				},
+				"Users": {
+					"type": "array",
+					"items": { "$ref": "#/definitions/github.com~1acme~1api.User" }
+				}
+			}
+		}`, `{
+			"paths": {
+				"/users": { "get": { "responses": { "default": { "content": { "application/json": {
+					"schema": { "$ref": "#/components/schemas/github.com~1acme~1api.User" }
+				} } } } } }
+			},
+			"components": { "schemas": {} }
+		}`)
+		Expect(err).To(BeNil())
+
+		jq := Jq(api)
+		Expect(jq.String("components", "schemas", "github.com_acme_api.User", "type")).To(Equal("object"))
+		Expect(jq.String("components", "schemas", "Users", "items", "$ref")).To(
+			Equal("#/components/schemas/github.com_acme_api.User"),
+		)
+		Expect(jq.String(
+			"paths", "/users", "get", "responses", "default", "content", "application/json", "schema", "$ref",
+		)).To(Equal("#/components/schemas/github.com_acme_api.User"))
+	})
+
+	It("should not rename references in examples and extensions", func() {
+		api, err := PutSchemaIntoOpenAPI(`{
+			"definitions": {"a/b": {"type": "object"}}
+		}`, `{
+			"paths": {"/a": {"post": {"requestBody": {"content": {"application/json": {
+				"schema": {"$ref": "#/components/schemas/a~1b"},
+				"example": {"$ref": "#/components/schemas/a~1b"},
+				"examples": {"raw": {"value": {"$ref": "#/components/schemas/a~1b"}}}
+			}}}}}},
+			"x-links": [{"$ref": "#/components/schemas/a~1b"}],
+			"components": {"schemas": {"C": {
+				"properties": {"default": {"$ref": "#/components/schemas/a~1b"}},
+				"default": {"$ref": "#/components/schemas/a~1b"}
+			}}}
+		}`)
+		Expect(err).To(BeNil())
+
+		jq := Jq(api)
+		content := []string{"paths", "/a", "post", "requestBody", "content", "application/json"}
+		Expect(jq.String(append(content, "schema", "$ref")...)).To(Equal("#/components/schemas/a_b"))
+		Expect(jq.String(append(content, "example", "$ref")...)).To(Equal("#/components/schemas/a~1b"))
+		Expect(jq.String(append(content, "examples", "raw", "value", "$ref")...)).To(Equal("#/components/schemas/a~1b"))
+		Expect(jq.String("x-links", "0", "$ref")).To(Equal("#/components/schemas/a~1b"))
+		Expect(jq.String("components", "schemas", "C", "properties", "default", "$ref")).To(Equal("#/components/schemas/a_b"))
+		Expect(jq.String("components", "schemas", "C", "default", "$ref")).To(Equal("#/components/schemas/a~1b"))
+	})
+
+	It("should rename references in components named like example fields", func() {
+		api, err := PutSchemaIntoOpenAPI(`{
+			"definitions": {"a/b": {"type": "object"}}
+		}`, `{
+			"components": {
+				"parameters": {
+					"value": {"name": "value", "in": "query", "schema": {"$ref": "#/components/schemas/a~1b"}},
+					"example": {"name": "example", "in": "query", "schema": {"$ref": "#/components/schemas/a~1b"}}
+				},
+				"schemas": {}
+			}
+		}`)
+		Expect(err).To(BeNil())
+
+		jq := Jq(api)
+		Expect(jq.String("components", "parameters", "value", "schema", "$ref")).To(Equal("#/components/schemas/a_b"))
+		Expect(jq.String("components", "parameters", "example", "schema", "$ref")).To(Equal("#/components/schemas/a_b"))
+	})
+
+	It("should disambiguate names that collide after sanitizing", func() {
+		res, err := TranslateDefinitionsWithOptions(map[string]interface{}{
+			"a_b": map[string]interface{}{"type": "string"},
+			"a/b": map[string]interface{}{"type": "integer"},
+			"a b": map[string]interface{}{"$ref": "#/definitions/a~1b"},
+		}, Options{})
+		Expect(err).To(BeNil())
+		Expect(res).To(Equal(map[string]interface{}{
+			"a_b":   map[string]interface{}{"type": "string"},
+			"a_b_2": map[string]interface{}{"$ref": "#/components/schemas/a_b_3"},
+			"a_b_3": map[string]interface{}{"type": "integer"},
+		}))
+	})
+
+	It("should use custom name function", func() {
+		res, err := TranslateDefinitionsWithOptions(map[string]interface{}{
+			"github.com/acme/api.User": map[string]interface{}{"type": "object"},
+		}, Options{
+			NameFunc: func(name string) string {
+				return name[strings.LastIndex(name, "/")+1:]
+			},
+		})
+		Expect(err).To(BeNil())
+		Expect(res).To(HaveKey("api.User"))
+	})
+})
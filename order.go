@@ -0,0 +1,272 @@
+package jsonschema2openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// keyOrder is shape of JSON document which remembers order of keys of every object in it.
+// Children of arrays are keyed by index.
+type keyOrder struct {
+	keys     []string
+	children map[string]*keyOrder
+}
+
+// decodeOrdered reads JSON document from r in one pass, returning its value, with numbers as json.Number,
+// and order of keys of its objects. When maxDepth is positive, objects and arrays nested deeper fail
+// with errInputTooDeep before they are read.
+func decodeOrdered(r io.Reader, maxDepth int) (interface{}, *keyOrder, error) {
+	dec := json.NewDecoder(r)
+	dec.UseNumber()
+	value, order, err := readOrdered(dec, 0, maxDepth)
+	if err != nil {
+		return nil, nil, err
+	}
+	// like json.Unmarshal, reject anything except whitespace after value
+	if _, err := dec.Token(); err != io.EOF {
+		if err == nil {
+			err = errors.New("invalid data after top-level value")
+		}
+		return nil, nil, err
+	}
+	return value, order, nil
+}
+
+func readOrdered(dec *json.Decoder, depth, maxDepth int) (interface{}, *keyOrder, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, nil, err
+	}
+	delim, ok := tok.(json.Delim)
+	if !ok {
+		return tok, nil, nil
+	}
+	if maxDepth > 0 && depth >= maxDepth {
+		return nil, nil, errInputTooDeep
+	}
+	o := &keyOrder{children: make(map[string]*keyOrder)}
+	var obj map[string]interface{}
+	var arr []interface{}
+	if delim == '{' {
+		obj = make(map[string]interface{})
+	} else {
+		arr = make([]interface{}, 0)
+	}
+	for i := 0; dec.More(); i++ {
+		key := strconv.Itoa(i)
+		if delim == '{' {
+			tok, err := dec.Token()
+			if err != nil {
+				return nil, nil, err
+			}
+			key, _ = tok.(string)
+		}
+		value, child, err := readOrdered(dec, depth+1, maxDepth)
+		if err != nil {
+			return nil, nil, err
+		}
+		if obj != nil {
+			obj[key] = value
+		} else {
+			arr = append(arr, value)
+		}
+		o.set(key, child)
+	}
+	if _, err := dec.Token(); err != nil { // closing delimiter
+		return nil, nil, err
+	}
+	if obj != nil {
+		return obj, o, nil
+	}
+	return arr, o, nil
+}
+
+// child returns order of value under key, nil when it is not known
+func (o *keyOrder) child(key string) *keyOrder {
+	if o == nil {
+		return nil
+	}
+	return o.children[key]
+}
+
+// at returns order of value at path of unescaped keys
+func (o *keyOrder) at(path ...string) *keyOrder {
+	for _, key := range path {
+		o = o.child(key)
+	}
+	return o
+}
+
+// set puts order of value under key, appending key if it was not known
+func (o *keyOrder) set(key string, child *keyOrder) {
+	if _, ok := o.children[key]; !ok {
+		o.keys = append(o.keys, key)
+	}
+	o.children[key] = child
+}
+
+// sort returns keys of object: known ones in their order first, and then the rest sorted
+func (o *keyOrder) sort(obj map[string]interface{}) []string {
+	res := make([]string, 0, len(obj))
+	seen := make(map[string]bool)
+	if o != nil {
+		for _, k := range o.keys {
+			if _, ok := obj[k]; ok {
+				res = append(res, k)
+				seen[k] = true
+			}
+		}
+	}
+	rest := make([]string, 0, len(obj)-len(res))
+	for k := range obj {
+		if !seen[k] {
+			rest = append(rest, k)
+		}
+	}
+	sort.Strings(rest)
+	return append(res, rest...)
+}
+
+// marshalOrdered is json.MarshalIndent with keys of objects in given order, and without escaping of <, > and &
+func marshalOrdered(value interface{}, order *keyOrder, indent string) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := newOrderedEncoder(&buf, indent).encode(value, order, 0); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// orderedWriter is implemented by bytes.Buffer and bufio.Writer, which keep errors until output is finished
+type orderedWriter interface {
+	io.Writer
+	io.ByteWriter
+	WriteString(s string) (int, error)
+}
+
+// orderedEncoder writes indented JSON with keys of objects in given order, formatted like by json.MarshalIndent
+type orderedEncoder struct {
+	w      orderedWriter
+	indent string
+	// scalar values are encoded into scratch
+	scratch bytes.Buffer
+	enc     *json.Encoder
+}
+
+func newOrderedEncoder(w orderedWriter, indent string) *orderedEncoder {
+	e := &orderedEncoder{w: w, indent: indent}
+	e.enc = json.NewEncoder(&e.scratch)
+	e.enc.SetEscapeHTML(false)
+	return e
+}
+
+func (e *orderedEncoder) encode(value interface{}, order *keyOrder, depth int) error {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		if len(v) == 0 {
+			e.w.WriteString("{}")
+			return nil
+		}
+		e.w.WriteByte('{')
+		for i, k := range order.sort(v) {
+			if i > 0 {
+				e.w.WriteByte(',')
+			}
+			e.newline(depth + 1)
+			if err := e.scalar(k, depth+1); err != nil {
+				return err
+			}
+			e.w.WriteString(": ")
+			if err := e.encode(v[k], order.child(k), depth+1); err != nil {
+				return err
+			}
+		}
+		e.newline(depth)
+		e.w.WriteByte('}')
+	case []interface{}:
+		if len(v) == 0 {
+			e.w.WriteString("[]")
+			return nil
+		}
+		e.w.WriteByte('[')
+		for i, elem := range v {
+			if i > 0 {
+				e.w.WriteByte(',')
+			}
+			e.newline(depth + 1)
+			if err := e.encode(elem, order.child(strconv.Itoa(i)), depth+1); err != nil {
+				return err
+			}
+		}
+		e.newline(depth)
+		e.w.WriteByte(']')
+	case bool:
+		e.w.WriteString(strconv.FormatBool(v))
+	case nil:
+		e.w.WriteString("null")
+	default:
+		return e.scalar(v, depth)
+	}
+	return nil
+}
+
+func (e *orderedEncoder) newline(depth int) {
+	e.w.WriteByte('\n')
+	for i := 0; i < depth; i++ {
+		e.w.WriteString(e.indent)
+	}
+}
+
+// scalar writes value with json.Encoder. Values of other Go types, which are encoded into
+// objects or arrays, are indented too.
+func (e *orderedEncoder) scalar(value interface{}, depth int) error {
+	e.scratch.Reset()
+	if err := e.enc.Encode(value); err != nil {
+		return err
+	}
+	data := bytes.TrimSuffix(e.scratch.Bytes(), []byte("\n"))
+	if len(data) > 0 && (data[0] == '{' || data[0] == '[') {
+		var indented bytes.Buffer
+		if err := json.Indent(&indented, data, strings.Repeat(e.indent, depth), e.indent); err != nil {
+			return err
+		}
+		data = indented.Bytes()
+	}
+	e.w.Write(data)
+	return nil
+}
+
+// componentsOrder adds translated definitions to order of components/schemas,
+// after components which are already there, in order they were written in schema.
+// rootName is name of root schema component, when it was included.
+func (t *translation) componentsOrder(components, schema *keyOrder, rootName string) {
+	name := func(n string) string {
+		if newName, ok := t.renames[n]; ok {
+			return newName
+		}
+		return n
+	}
+	if rootName != "" {
+		components.set(name(rootName), schema)
+	}
+	definitions := schema.child("definitions")
+	if definitions != nil {
+		for _, k := range definitions.keys {
+			components.set(name(k), definitions.children[k])
+		}
+	}
+	// hoisted definitions keep order of their keys, and are sorted by name among themselves
+	for pointer, newName := range t.moved {
+		source := definitions
+		for _, token := range strings.Split(pointer, "/") {
+			source = source.child(unescapeRefToken(token))
+		}
+		if _, ok := components.children[name(newName)]; !ok && source != nil {
+			components.children[name(newName)] = source
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package jsonschema2openapi
+
+import (
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+// expectInOrder checks that every of substrings is found in s after the previous one
+func expectInOrder(s string, substrings ...string) {
+	position := 0
+	for _, sub := range substrings {
+		i := strings.Index(s[position:], sub)
+		Expect(i).To(BeNumerically(">=", 0), "%q not found after position %d in\n%s", sub, position, s)
+		position += i + len(sub)
+	}
+}
+
+const orderTemplate = `{
+	"openapi": "3.0.0",
+	"info": {"version": "1.0.0", "title": "My API"},
+	"paths": {
+		"/b": {"get": {"responses": {"default": {"description": "B"}}}},
+		"/a": {"get": {"responses": {"default": {"description": "A"}}}}
+	},
+	"components": {"schemas": {"Existing": {"type": "string"}}}
+}`
+
+var _ = Describe("PutSchemaIntoOpenAPI output", func() {
+	It("should keep order of template and schema keys", func() {
+		spec, err := PutSchemaIntoOpenAPIWithOptions(`{
+			"title": "Root",
+			"type": "object",
+			"properties": {"zeta": {"type": "string"}, "alpha": {"$ref": "#/definitions/Pet"}},
+			"definitions": {
+				"Pet": {
+					"type": "object",
+					"required": ["name"],
+					"properties": {"name": {"type": "string"}, "age": {"type": "integer"}},
+					"definitions": {"Tag": {"type": "string", "format": "uuid"}}
+				},
+				"map[string]int": {"type": "object"},
+				"Animal": {"type": "object"}
+			}
+		}`, orderTemplate, Options{IncludeRoot: true})
+		Expect(err).To(BeNil())
+		expectInOrder(spec,
+			`"openapi"`, `"info"`, `"version"`, `"title"`, `"paths"`, `"/b"`, `"/a"`,
+			`"components"`, `"Existing"`,
+			`"Root"`, `"type"`, `"properties"`, `"zeta"`, `"alpha"`,
+			`"Pet"`, `"type"`, `"required"`, `"properties"`, `"name"`, `"age"`,
+			`"map_string_int"`, `"Animal"`,
+			`"Tag"`, `"type"`, `"format"`,
+		)
+	})
+
+	It("should give byte-identical output for identical input", func() {
+		schema := `{"definitions": {
+			"B": {"properties": {"y": {}, "x": {}}, "x-b": 1, "x-a": 2},
+			"A": {"properties": {"q": {"type": "integer"}, "p": {"type": "string"}}}
+		}}`
+		first, err := PutSchemaIntoOpenAPI(schema, orderTemplate)
+		Expect(err).To(BeNil())
+		for i := 0; i < 20; i++ {
+			Expect(PutSchemaIntoOpenAPI(schema, orderTemplate)).To(Equal(first))
+		}
+		expectInOrder(first, `"Existing"`, `"B"`, `"y"`, `"x"`, `"x-b"`, `"x-a"`, `"A"`, `"q"`, `"p"`)
+	})
+})
+
+var _ = Describe("ExtractSchemaFromOpenAPI output", func() {
+	It("should keep order of components", func() {
+		schema, err := ExtractSchemaFromOpenAPI(`{"components": {"schemas": {
+			"Zebra": {"properties": {"stripes": {"type": "integer"}, "color": {"type": "string"}}},
+			"Ant": {"type": "object"}
+		}}}`)
+		Expect(err).To(BeNil())
+		expectInOrder(schema, `"$schema"`, `"definitions"`, `"Zebra"`, `"stripes"`, `"color"`, `"Ant"`)
+	})
+})
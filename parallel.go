@@ -0,0 +1,57 @@
+package jsonschema2openapi
+
+import (
+	"context"
+	"sync"
+)
+
+// visitParallel translates definitions by opts.Workers goroutines. Every definition is visited by its own
+// translation, which collects errors and diagnostics. They are added to t in order of names,
+// as if definitions were translated one after another.
+func (t *translation) visitParallel(ctx context.Context, names []string, definitions map[string]interface{}) error {
+	results := make([]interface{}, len(names))
+	parts := make([]*translation, len(names))
+	diagnostics := make([][]Diagnostic, len(names))
+
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < t.opts.Workers && w < len(names); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				part := &translation{opts: t.opts, renames: t.renames}
+				if t.opts.OnDiagnostic != nil {
+					i := i
+					part.opts.OnDiagnostic = func(d Diagnostic) {
+						diagnostics[i] = append(diagnostics[i], d)
+					}
+				}
+				results[i] = part.visit(componentsPrefix+escapeRefToken(names[i]), definitions[names[i]])
+				parts[i] = part
+			}
+		}()
+	}
+send:
+	for i := range names {
+		select {
+		case jobs <- i:
+		case <-ctx.Done():
+			break send
+		}
+	}
+	close(jobs)
+	wg.Wait()
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	for i, name := range names {
+		t.schemas[name] = results[i]
+		t.errors = append(t.errors, parts[i].errors...)
+		for _, d := range diagnostics[i] {
+			t.opts.OnDiagnostic(d)
+		}
+	}
+	return nil
+}
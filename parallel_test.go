@@ -0,0 +1,56 @@
+package jsonschema2openapi
+
+import (
+	"context"
+	"encoding/json"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Parallel translation", func() {
+	// translateWith returns translated definitions, error and diagnostics
+	translateWith := func(definitions map[string]interface{}, opts Options) (string, error, []Diagnostic) {
+		var diagnostics []Diagnostic
+		opts.OnDiagnostic = func(d Diagnostic) {
+			diagnostics = append(diagnostics, d)
+		}
+		res, err := TranslateDefinitionsWithOptions(definitions, opts)
+		data, _ := json.Marshal(res)
+		return string(data), err, diagnostics
+	}
+
+	It("should give the same result and diagnostics as sequential translation", func() {
+		definitions := syntheticSchema(50)["definitions"].(map[string]interface{})
+		for k, v := range decodeDefinitions(everyPassJSON) {
+			definitions[k] = v
+		}
+		opts := Options{GenerateExamples: true, Keywords: map[string]string{"goType": "x-go-type"}}
+		expected, err, expectedDiagnostics := translateWith(definitions, opts)
+		Expect(err).To(BeNil())
+		Expect(expectedDiagnostics).NotTo(BeEmpty())
+
+		for _, workers := range []int{2, 4, 100} {
+			opts.Workers = workers
+			res, err, diagnostics := translateWith(definitions, opts)
+			Expect(err).To(BeNil())
+			Expect(res).To(MatchJSON(expected))
+			Expect(diagnostics).To(Equal(expectedDiagnostics))
+		}
+	})
+
+	It("should report the same errors as sequential translation", func() {
+		definitions := decodeDefinitions(everyPassJSON)
+		_, expected, _ := translateWith(definitions, Options{UnknownKeywords: RejectUnknown})
+		Expect(expected).NotTo(BeNil())
+		_, err, _ := translateWith(definitions, Options{UnknownKeywords: RejectUnknown, Workers: 3})
+		Expect(err).To(Equal(expected))
+	})
+
+	It("should stop when context is cancelled", func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		_, err := translate(ctx, syntheticSchema(10)["definitions"].(map[string]interface{}), Options{Workers: 4})
+		Expect(err).To(Equal(context.Canceled))
+	})
+})
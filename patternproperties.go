@@ -0,0 +1,99 @@
+package jsonschema2openapi
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// patternProperties approximates "patternProperties" with "additionalProperties", as OpenAPI 3.0 has no way
+// to restrict property names. Schema of single pattern becomes schema of additional properties,
+// schemas of several patterns are combined with anyOf. Original patterns are kept in "x-patternProperties".
+// Named properties matching patterns have to match schemas of those patterns too, so they are combined with allOf.
+func (t *translation) patternProperties(pointer string, schema map[string]interface{}) map[string]interface{} {
+	patternProperties, ok := schema["patternProperties"].(map[string]interface{})
+	if !ok {
+		return schema
+	}
+	delete(schema, "patternProperties")
+	if len(patternProperties) == 0 {
+		return schema
+	}
+	schema["x-patternProperties"] = patternProperties
+
+	patterns := make([]string, 0, len(patternProperties))
+	for p := range patternProperties {
+		patterns = append(patterns, p)
+	}
+	sort.Strings(patterns)
+	t.constrainProperties(pointer, schema, patterns, patternProperties)
+	alternatives := make([]interface{}, 0, len(patterns)+1)
+	for _, p := range patterns {
+		alternatives = append(alternatives, patternProperties[p])
+	}
+
+	switch additional := schema["additionalProperties"].(type) {
+	case nil:
+		t.reportNarrowed(pointer, patterns)
+	case bool:
+		if additional {
+			t.reportNarrowed(pointer, patterns)
+		} else {
+			t.report(pointer, "additional properties not matching %s are allowed", strings.Join(patterns, ", "))
+		}
+	case map[string]interface{}:
+		if len(additional) > 0 {
+			alternatives = append(alternatives, additional)
+			t.report(pointer, "additionalProperties are merged with schemas of patternProperties")
+		} else {
+			t.reportNarrowed(pointer, patterns)
+		}
+	}
+	if len(alternatives) == 1 {
+		schema["additionalProperties"] = alternatives[0]
+	} else {
+		schema["additionalProperties"] = map[string]interface{}{
+			"anyOf": alternatives,
+		}
+	}
+	t.report(pointer, "property names are not checked against patterns %s", strings.Join(patterns, ", "))
+	return schema
+}
+
+// constrainProperties combines schema of every property with schemas of patterns its name matches
+func (t *translation) constrainProperties(pointer string, schema map[string]interface{}, patterns []string, patternProperties map[string]interface{}) {
+	properties, ok := schema["properties"].(map[string]interface{})
+	if !ok || len(properties) == 0 {
+		return
+	}
+	regexps := make([]*regexp.Regexp, len(patterns))
+	for i, p := range patterns {
+		re, err := regexp.Compile(p)
+		if err != nil {
+			t.report(pointer, "pattern %s is not supported, so properties are not checked against its schema", p)
+			continue
+		}
+		regexps[i] = re
+	}
+	constrained := make(map[string]interface{}, len(properties))
+	for name, property := range properties {
+		allOf := []interface{}{property}
+		for i, re := range regexps {
+			if re != nil && re.MatchString(name) {
+				allOf = append(allOf, patternProperties[patterns[i]])
+			}
+		}
+		if len(allOf) == 1 {
+			constrained[name] = property
+		} else {
+			constrained[name] = map[string]interface{}{"allOf": allOf}
+		}
+	}
+	schema["properties"] = constrained
+}
+
+// reportNarrowed reports that any values of properties not matching patterns were allowed,
+// but now they have to match schemas of patterns
+func (t *translation) reportNarrowed(pointer string, patterns []string) {
+	t.report(pointer, "additional properties not matching %s have to match their schemas", strings.Join(patterns, ", "))
+}
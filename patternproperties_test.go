@@ -0,0 +1,120 @@
+package jsonschema2openapi
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Pattern properties", func() {
+	var (
+		diagnostics []Diagnostic
+		opts        Options
+	)
+	BeforeEach(func() {
+		diagnostics = nil
+		opts = Options{OnDiagnostic: func(d Diagnostic) {
+			diagnostics = append(diagnostics, d)
+		}}
+	})
+
+	It("should become additionalProperties", func() {
+		res, err := TranslateDefinitionsWithOptions(map[string]interface{}{
+			"Labels": map[string]interface{}{
+				"type": "object",
+				"patternProperties": map[string]interface{}{
+					"^[a-z]+$": map[string]interface{}{"type": "string"},
+				},
+			},
+		}, opts)
+		Expect(err).To(BeNil())
+		Expect(res).To(Equal(map[string]interface{}{
+			"Labels": map[string]interface{}{
+				"type":                 "object",
+				"additionalProperties": map[string]interface{}{"type": "string"},
+				"x-patternProperties": map[string]interface{}{
+					"^[a-z]+$": map[string]interface{}{"type": "string"},
+				},
+			},
+		}))
+		Expect(diagnostics).To(HaveLen(2))
+		Expect(diagnostics[0].Pointer).To(Equal("#/components/schemas/Labels"))
+	})
+
+	It("should report that other properties have to match schemas of patterns", func() {
+		for _, additional := range []interface{}{nil, true, map[string]interface{}{}} {
+			diagnostics = nil
+			schema := map[string]interface{}{
+				"patternProperties": map[string]interface{}{
+					"^[a-z]+$": map[string]interface{}{"type": "string"},
+				},
+			}
+			if additional != nil {
+				schema["additionalProperties"] = additional
+			}
+			_, err := TranslateDefinitionsWithOptions(map[string]interface{}{"Labels": schema}, opts)
+			Expect(err).To(BeNil())
+			Expect(diagnostics).To(Equal([]Diagnostic{
+				{Pointer: "#/components/schemas/Labels", Message: "additional properties not matching ^[a-z]+$ have to match their schemas"},
+				{Pointer: "#/components/schemas/Labels", Message: "property names are not checked against patterns ^[a-z]+$"},
+			}))
+		}
+	})
+
+	It("should combine several patterns with anyOf", func() {
+		res, err := TranslateDefinitionsWithOptions(map[string]interface{}{
+			"Labels": map[string]interface{}{
+				"patternProperties": map[string]interface{}{
+					"^s_": map[string]interface{}{"type": "string"},
+					"^i_": map[string]interface{}{"type": "integer"},
+				},
+				"additionalProperties": false,
+			},
+		}, opts)
+		Expect(err).To(BeNil())
+		Expect(res).To(HaveKeyWithValue("Labels", HaveKeyWithValue("additionalProperties", map[string]interface{}{
+			"anyOf": []interface{}{
+				map[string]interface{}{"type": "integer"},
+				map[string]interface{}{"type": "string"},
+			},
+		})))
+		Expect(diagnostics).To(HaveLen(2))
+	})
+
+	It("should apply schemas of patterns to properties matching them", func() {
+		res, err := TranslateDefinitionsWithOptions(map[string]interface{}{
+			"Labels": map[string]interface{}{
+				"properties": map[string]interface{}{
+					"s_name": map[string]interface{}{"maxLength": 10.0},
+					"other":  map[string]interface{}{"type": "boolean"},
+				},
+				"patternProperties": map[string]interface{}{
+					"^s_": map[string]interface{}{"type": "string"},
+					"_":   map[string]interface{}{"minLength": 1.0},
+				},
+			},
+		}, opts)
+		Expect(err).To(BeNil())
+		Expect(res).To(HaveKeyWithValue("Labels", HaveKeyWithValue("properties", map[string]interface{}{
+			"s_name": map[string]interface{}{"allOf": []interface{}{
+				map[string]interface{}{"maxLength": 10.0},
+				map[string]interface{}{"type": "string"},
+				map[string]interface{}{"minLength": 1.0},
+			}},
+			"other": map[string]interface{}{"type": "boolean"},
+		})))
+	})
+
+	It("should report patterns which can not be applied to properties", func() {
+		_, err := TranslateDefinitionsWithOptions(map[string]interface{}{
+			"Labels": map[string]interface{}{
+				"properties":        map[string]interface{}{"a": map[string]interface{}{}},
+				"patternProperties": map[string]interface{}{"^(?!x)": map[string]interface{}{"type": "string"}},
+			},
+		}, opts)
+		Expect(err).To(BeNil())
+		Expect(diagnostics).To(ContainElement(Diagnostic{
+			Pointer: "#/components/schemas/Labels",
+			Message: "pattern ^(?!x) is not supported, so properties are not checked against its schema",
+		}))
+	})
+})
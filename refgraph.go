@@ -0,0 +1,339 @@
+package jsonschema2openapi
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// RefGraph describes references between components/schemas of OpenAPI spec,
+// and from the rest of spec, like paths, to them
+type RefGraph struct {
+	// Refs maps every component to sorted names of components it references
+	Refs map[string][]string
+	// Dangling lists references to components, or to other parts of spec, which do not exist
+	Dangling []Ref
+	// Cycles are groups of components which reference each other, directly or through other components.
+	// Components in every cycle, and cycles themselves, are sorted. Cycles are allowed: translation rewrites
+	// references without following them, and GenerateExample, which does follow them, leaves out component
+	// which is already being sampled once it is deep enough, or gives null when it is required.
+	// Other tools which inline references have to stop on them.
+	Cycles [][]string
+	// Paths maps every component which is reachable from outside of components/schemas to the shortest chain
+	// of references by which it is reached. Every reference in chain is given by pointer of its $ref.
+	Paths map[string][]string
+	// Unreachable lists sorted names of components which are not used by the rest of spec
+	Unreachable []string
+
+	// edges are references found in every component, in order of their pointers
+	edges map[string][]Ref
+}
+
+// Ref is reference found in spec
+type Ref struct {
+	// Pointer is JSON pointer to object which has reference, like "#/components/schemas/Pet/properties/owner".
+	// Pointer of discriminator mapping ends with "/discriminator/mapping/" and value of discriminator.
+	Pointer string
+	Ref     string
+}
+
+func (r Ref) String() string {
+	return r.Pointer + ": " + r.Ref
+}
+
+// DanglingRefsError lists references which point at nothing
+type DanglingRefsError []Ref
+
+func (errs DanglingRefsError) Error() string {
+	messages := make([]string, len(errs))
+	for i, e := range errs {
+		messages[i] = e.String()
+	}
+	return fmt.Sprintf("Error %s. References point at nothing", strings.Join(messages, "; "))
+}
+
+// AnalyzeRefs builds graph of references in OpenAPI spec. References inside of examples, defaults, enums
+// and extensions are not counted, references of discriminator mappings, and of "x-tuple" and "x-patternProperties"
+// made by translation, are.
+func AnalyzeRefs(spec map[string]interface{}) *RefGraph {
+	var refs []Ref
+	collectRefs(spec, "#", false, &refs)
+	sort.SliceStable(refs, func(i, j int) bool {
+		return refs[i].Pointer < refs[j].Pointer
+	})
+
+	components, _ := jsonMap(spec["components"])["schemas"].(map[string]interface{})
+	g := &RefGraph{
+		Refs:  make(map[string][]string, len(components)),
+		Paths: make(map[string][]string),
+		edges: make(map[string][]Ref),
+	}
+	for name := range components {
+		g.Refs[name] = []string{}
+	}
+	var roots []Ref
+	for _, r := range refs {
+		target, ok := refTarget(spec, r.Ref)
+		if !ok {
+			g.Dangling = append(g.Dangling, r)
+			continue
+		}
+		source, _, inComponent := splitRef(r.Pointer, componentsPrefix)
+		switch {
+		case target == "":
+			// reference to other part of spec
+		case inComponent:
+			g.edges[source] = append(g.edges[source], r)
+			if !containsString(g.Refs[source], target) {
+				g.Refs[source] = append(g.Refs[source], target)
+			}
+		default:
+			roots = append(roots, r)
+		}
+	}
+	for _, targets := range g.Refs {
+		sort.Strings(targets)
+	}
+	g.reach(roots)
+	for name := range components {
+		if _, ok := g.Paths[name]; !ok {
+			g.Unreachable = append(g.Unreachable, name)
+		}
+	}
+	sort.Strings(g.Unreachable)
+	g.Cycles = findCycles(g.Refs)
+	return g
+}
+
+// Err returns DanglingRefsError if there are dangling references, or nil otherwise
+func (g *RefGraph) Err() error {
+	if len(g.Dangling) > 0 {
+		return DanglingRefsError(g.Dangling)
+	}
+	return nil
+}
+
+// reach finds shortest paths to components, going from roots breadth first
+func (g *RefGraph) reach(roots []Ref) {
+	var queue []string
+	visit := func(path []string, r Ref) {
+		name, _, _ := splitRef(r.Ref, componentsPrefix)
+		if _, ok := g.Paths[name]; ok {
+			return
+		}
+		g.Paths[name] = append(append([]string{}, path...), r.Pointer)
+		queue = append(queue, name)
+	}
+	for _, r := range roots {
+		visit(nil, r)
+	}
+	for len(queue) > 0 {
+		name := queue[0]
+		queue = queue[1:]
+		for _, r := range g.edges[name] {
+			visit(g.Paths[name], r)
+		}
+	}
+}
+
+// prune returns copy of spec without unreachable components
+func (g *RefGraph) prune(spec map[string]interface{}) map[string]interface{} {
+	components, ok := spec["components"].(map[string]interface{})
+	if !ok || len(g.Unreachable) == 0 {
+		return spec
+	}
+	schemas, _ := components["schemas"].(map[string]interface{})
+	pruned := make(map[string]interface{}, len(schemas))
+	for name, schema := range schemas {
+		if _, ok := g.Paths[name]; ok {
+			pruned[name] = schema
+		}
+	}
+	res := make(map[string]interface{}, len(spec))
+	for k, v := range spec {
+		res[k] = v
+	}
+	resComponents := make(map[string]interface{}, len(components))
+	for k, v := range components {
+		resComponents[k] = v
+	}
+	resComponents["schemas"] = pruned
+	res["components"] = resComponents
+	return res
+}
+
+// collectRefs appends references in jsonData at pointer to refs. Inside of schemas keys of properties
+// and similar keywords are not mistaken for keywords, and values of data keywords are skipped.
+// Outside of them values of examples are skipped, see isExampleData.
+func collectRefs(jsonData interface{}, pointer string, inSchema bool, refs *[]Ref) {
+	switch v := jsonData.(type) {
+	case map[string]interface{}:
+		if ref, ok := v["$ref"].(string); ok {
+			*refs = append(*refs, Ref{Pointer: pointer, Ref: ref})
+		}
+		for k, value := range v {
+			p := pointer + "/" + escapeRefToken(k)
+			switch {
+			case k == "$ref":
+			case inSchema && schemaExtensions[k]:
+				collectRefs(value, p, true, refs)
+			case inSchema && schemaMapExtensions[k]:
+				collectSchemaMapRefs(value, p, refs)
+			case isExtension(k):
+			case inSchema && schemaMapKeywords[k]:
+				collectSchemaMapRefs(value, p, refs)
+			case inSchema && k == "discriminator":
+				mapping, _ := jsonMap(value)["mapping"].(map[string]interface{})
+				for name, ref := range mapping {
+					if ref, ok := ref.(string); ok {
+						*refs = append(*refs, Ref{Pointer: p + "/mapping/" + escapeRefToken(name), Ref: ref})
+					}
+				}
+			case inSchema && dataKeywords[k]:
+			case !inSchema && isExampleData(pointer, k):
+			case !inSchema && pointer == "#/components" && k == "schemas":
+				collectSchemaMapRefs(value, p, refs)
+			case !inSchema && k == "schema":
+				collectRefs(value, p, true, refs)
+			default:
+				collectRefs(value, p, inSchema, refs)
+			}
+		}
+	case []interface{}:
+		for i, elem := range v {
+			collectRefs(elem, pointer+"/"+strconv.Itoa(i), inSchema, refs)
+		}
+	}
+}
+
+// collectSchemaMapRefs applies collectRefs to every schema of object like properties or components/schemas
+func collectSchemaMapRefs(jsonData interface{}, pointer string, refs *[]Ref) {
+	for name, schema := range jsonMap(jsonData) {
+		collectRefs(schema, pointer+"/"+escapeRefToken(name), true, refs)
+	}
+}
+
+func jsonMap(jsonData interface{}) map[string]interface{} {
+	m, _ := jsonData.(map[string]interface{})
+	return m
+}
+
+// refTarget returns name of component which reference points to, or empty name when it points
+// to other part of spec. Not ok is returned when reference points at nothing.
+// External references and references to anchors are not checked.
+func refTarget(spec map[string]interface{}, ref string) (name string, ok bool) {
+	if ref != "#" && !strings.HasPrefix(ref, "#/") {
+		return "", true
+	}
+	if _, ok := resolvePointer(spec, ref); !ok {
+		return "", false
+	}
+	name, _, _ = splitRef(ref, componentsPrefix)
+	return name, true
+}
+
+// resolvePointer returns value of document at JSON pointer like "#/components/schemas/Pet"
+func resolvePointer(document interface{}, pointer string) (interface{}, bool) {
+	pointer = strings.TrimPrefix(pointer, "#")
+	if pointer == "" {
+		return document, true
+	}
+	value := document
+	for _, token := range strings.Split(pointer[1:], "/") {
+		if unescaped, err := url.PathUnescape(token); err == nil {
+			token = unescaped
+		}
+		token = unescapeRefToken(token)
+		switch v := value.(type) {
+		case map[string]interface{}:
+			child, ok := v[token]
+			if !ok {
+				return nil, false
+			}
+			value = child
+		case []interface{}:
+			i, err := strconv.Atoi(token)
+			if err != nil || i < 0 || i >= len(v) {
+				return nil, false
+			}
+			value = v[i]
+		default:
+			return nil, false
+		}
+	}
+	return value, true
+}
+
+func containsString(list []string, s string) bool {
+	for _, e := range list {
+		if e == s {
+			return true
+		}
+	}
+	return false
+}
+
+// findCycles returns strongly connected components of graph which have cycles in them,
+// found by Tarjan's algorithm
+func findCycles(graph map[string][]string) [][]string {
+	names := make([]string, 0, len(graph))
+	for name := range graph {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	index := make(map[string]int)
+	lowlink := make(map[string]int)
+	onStack := make(map[string]bool)
+	var stack []string
+	var cycles [][]string
+	var connect func(name string)
+	connect = func(name string) {
+		index[name] = len(index)
+		lowlink[name] = index[name]
+		stack = append(stack, name)
+		onStack[name] = true
+		selfLoop := false
+		for _, next := range graph[name] {
+			if next == name {
+				selfLoop = true
+			}
+			if _, ok := index[next]; !ok {
+				connect(next)
+				if lowlink[next] < lowlink[name] {
+					lowlink[name] = lowlink[next]
+				}
+			} else if onStack[next] && index[next] < lowlink[name] {
+				lowlink[name] = index[next]
+			}
+		}
+		if lowlink[name] != index[name] {
+			return
+		}
+		var component []string
+		for {
+			last := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			onStack[last] = false
+			component = append(component, last)
+			if last == name {
+				break
+			}
+		}
+		if len(component) > 1 || selfLoop {
+			sort.Strings(component)
+			cycles = append(cycles, component)
+		}
+	}
+	for _, name := range names {
+		if _, ok := index[name]; !ok {
+			connect(name)
+		}
+	}
+	sort.Slice(cycles, func(i, j int) bool {
+		return cycles[i][0] < cycles[j][0]
+	})
+	return cycles
+}
@@ -0,0 +1,174 @@
+package jsonschema2openapi
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+const refsTemplate = `{
+	"openapi": "3.0.0",
+	"info": {"version": "1.0.0", "title": "Pets"},
+	"paths": {
+		"/pets": {
+			"get": {
+				"parameters": [{"$ref": "#/components/parameters/Limit"}],
+				"responses": {
+					"200": {
+						"description": "Pets",
+						"content": {"application/json": {"schema": {"type": "array", "items": {"$ref": "#/components/schemas/Pet"}}}}
+					},
+					"default": {"$ref": "#/components/responses/Error"}
+				}
+			}
+		}
+	},
+	"components": {
+		"parameters": {"Limit": {"name": "limit", "in": "query", "schema": {"$ref": "#/components/schemas/Limit"}}},
+		"responses": {"Error": {"description": "Error", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/Error"}}}}},
+		"schemas": {}
+	}
+}`
+
+const refsSchema = `{
+	"definitions": {
+		"Pet": {
+			"properties": {
+				"owner": {"$ref": "#/definitions/Person"},
+				"kind": {"$ref": "#/definitions/Kind"},
+				"default": {"type": "string", "default": {"$ref": "#/definitions/Data"}}
+			},
+			"x-see": {"$ref": "#/definitions/Extension"}
+		},
+		"Person": {"properties": {"pets": {"items": {"$ref": "#/definitions/Pet"}}, "self": {"$ref": "#/definitions/Person"}}},
+		"Kind": {"enum": ["cat", "dog"]},
+		"Limit": {"type": "integer"},
+		"Error": {"properties": {"cause": {"$ref": "#/definitions/Error"}}},
+		"Unused": {"$ref": "#/definitions/AlsoUnused"},
+		"AlsoUnused": {"$ref": "#/definitions/Missing"}
+	}
+}`
+
+var _ = Describe("AnalyzeRefs", func() {
+	var graph *RefGraph
+
+	BeforeEach(func() {
+		var spec map[string]interface{}
+		api, err := PutSchemaIntoOpenAPI(refsSchema, refsTemplate)
+		Expect(err).To(BeNil())
+		Expect(decodeJSON([]byte(api), &spec)).To(Succeed())
+		graph = AnalyzeRefs(spec)
+	})
+
+	It("should find references between components", func() {
+		Expect(graph.Refs).To(Equal(map[string][]string{
+			"Pet":        {"Kind", "Person"},
+			"Person":     {"Person", "Pet"},
+			"Kind":       {},
+			"Limit":      {},
+			"Error":      {"Error"},
+			"Unused":     {"AlsoUnused"},
+			"AlsoUnused": {},
+		}))
+	})
+
+	It("should report dangling references", func() {
+		Expect(graph.Dangling).To(Equal([]Ref{
+			{Pointer: "#/components/schemas/AlsoUnused", Ref: "#/components/schemas/Missing"},
+		}))
+		Expect(graph.Err()).To(MatchError("Error #/components/schemas/AlsoUnused: #/components/schemas/Missing. References point at nothing"))
+	})
+
+	It("should detect cycles", func() {
+		Expect(graph.Cycles).To(Equal([][]string{{"Error"}, {"Person", "Pet"}}))
+	})
+
+	It("should name the shortest path by which component is reached", func() {
+		Expect(graph.Paths).To(Equal(map[string][]string{
+			"Error": {"#/components/responses/Error/content/application~1json/schema"},
+			"Limit": {"#/components/parameters/Limit/schema"},
+			"Pet":   {"#/paths/~1pets/get/responses/200/content/application~1json/schema/items"},
+			"Kind": {
+				"#/paths/~1pets/get/responses/200/content/application~1json/schema/items",
+				"#/components/schemas/Pet/properties/kind",
+			},
+			"Person": {
+				"#/paths/~1pets/get/responses/200/content/application~1json/schema/items",
+				"#/components/schemas/Pet/properties/owner",
+			},
+		}))
+		Expect(graph.Unreachable).To(Equal([]string{"AlsoUnused", "Unused"}))
+	})
+
+	It("should count references of discriminator mapping", func() {
+		graph := AnalyzeRefs(map[string]interface{}{
+			"paths": map[string]interface{}{"/events": map[string]interface{}{"schema": map[string]interface{}{"$ref": "#/components/schemas/Event"}}},
+			"components": map[string]interface{}{"schemas": TranslateDefinitions(decodeDefinitions(`{"definitions": {
+				"Event": {"oneOf": [
+					{"if": {"properties": {"version": {"enum": ["v1"]}}}, "then": {"$ref": "#/definitions/V1"}, "else": {"properties": {"version": {"enum": ["v1"]}}}}
+				]},
+				"V1": {"type": "object"}
+			}}`))},
+		})
+		Expect(graph.Refs["Event"]).To(Equal([]string{"V1"}))
+		Expect(graph.Dangling).To(BeEmpty())
+		Expect(graph.Unreachable).To(BeEmpty())
+	})
+
+	It("should keep components used only by tuples and pattern properties kept in extensions", func() {
+		graph := AnalyzeRefs(map[string]interface{}{
+			"paths": map[string]interface{}{"/t": map[string]interface{}{"$ref": "#/components/schemas/T"}},
+			"components": map[string]interface{}{"schemas": map[string]interface{}{
+				"T": map[string]interface{}{
+					"items":   map[string]interface{}{},
+					"x-tuple": map[string]interface{}{"items": []interface{}{map[string]interface{}{"$ref": "#/components/schemas/A"}}},
+					"x-patternProperties": map[string]interface{}{
+						"^b": map[string]interface{}{"$ref": "#/components/schemas/B"},
+					},
+					"x-see": map[string]interface{}{"$ref": "#/components/schemas/C"},
+				},
+				"A": map[string]interface{}{},
+				"B": map[string]interface{}{},
+				"C": map[string]interface{}{},
+			}},
+		})
+		Expect(graph.Refs["T"]).To(Equal([]string{"A", "B"}))
+		Expect(graph.Unreachable).To(Equal([]string{"C"}))
+	})
+})
+
+var _ = Describe("PutSchemaIntoOpenAPI with references checked", func() {
+	It("should fail on dangling references", func() {
+		_, err := PutSchemaIntoOpenAPIWithOptions(refsSchema, refsTemplate, Options{CheckRefs: true})
+		Expect(err).To(BeAssignableToTypeOf(DanglingRefsError{}))
+	})
+
+	It("should prune unreachable components", func() {
+		api, err := PutSchemaIntoOpenAPIWithOptions(refsSchema, refsTemplate, Options{PruneUnreachable: true})
+		Expect(err).To(BeNil())
+		Expect(api).To(ContainSubstring(`"Person": {`))
+		Expect(api).NotTo(ContainSubstring(`Unused`))
+	})
+
+	It("should keep components used by parts of template named like example fields", func() {
+		api, err := PutSchemaIntoOpenAPIWithOptions(`{
+			"definitions": {"A": {"type": "string"}, "B": {"type": "string"}}
+		}`, `{
+			"paths": {"/a": {"get": {
+				"parameters": [{"$ref": "#/components/parameters/value"}],
+				"responses": {"default": {"description": "A", "content": {"application/json": {
+					"example": {"$ref": "#/components/schemas/B"}
+				}}}}
+			}}},
+			"components": {
+				"parameters": {"value": {"name": "value", "in": "query", "schema": {"$ref": "#/components/schemas/A"}}},
+				"schemas": {}
+			}
+		}`, Options{CheckRefs: true, PruneUnreachable: true})
+		Expect(err).To(BeNil())
+
+		jq := Jq(api)
+		Expect(jq.String("components", "schemas", "A", "type")).To(Equal("string"))
+		_, err = jq.Object("components", "schemas", "B")
+		Expect(err).NotTo(BeNil())
+	})
+})
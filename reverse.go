@@ -0,0 +1,334 @@
+package jsonschema2openapi
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/jmoiron/jsonq"
+)
+
+const draft07 = "http://json-schema.org/draft-07/schema#"
+
+// ExtractSchemaFromOpenAPI returns JSON Schema draft-07 with definitions translated from components/schemas of OpenAPI spec
+func ExtractSchemaFromOpenAPI(openAPISpec string) (string, error) {
+	return ExtractSchemaFromOpenAPIWithOptions(openAPISpec, Options{})
+}
+
+// ExtractSchemaFromOpenAPIWithOptions is ExtractSchemaFromOpenAPI configured by opts
+func ExtractSchemaFromOpenAPIWithOptions(openAPISpec string, opts Options) (string, error) {
+	spec, specOrder, err := decodeInput(context.Background(), strings.NewReader(openAPISpec), "OpenAPI spec", Options{})
+	if err != nil {
+		return "", fmt.Errorf("Error %s. Not able to parse OpenAPI spec", err.Error())
+	}
+	components, err := jsonq.NewQuery(spec).Object("components", "schemas")
+	if err != nil {
+		return "", fmt.Errorf("Error %s. Bad OpenAPI spec, no component.schemas object", err.Error())
+	}
+	definitions, err := TranslateComponentsWithOptions(components, opts)
+	if err != nil {
+		return "", err
+	}
+	// keep definitions in order they were written in spec
+	order := &keyOrder{children: make(map[string]*keyOrder)}
+	order.set("$schema", nil)
+	order.set("definitions", specOrder.at("components", "schemas"))
+	res, err := marshalOrdered(map[string]interface{}{
+		"$schema":     draft07,
+		"definitions": definitions,
+	}, order, " ")
+	if err != nil {
+		return "", fmt.Errorf("Error %s. Not able to output JSON schema", err.Error())
+	}
+	return string(res), nil
+}
+
+// TranslateComponents translates OpenAPI components/schemas object to JSON Schema draft-07 definitions.
+// It is the inverse of TranslateDefinitions, and like it does not change components.
+func TranslateComponents(components map[string]interface{}) map[string]interface{} {
+	res, _ := TranslateComponentsWithOptions(components, Options{})
+	return res
+}
+
+// TranslateComponentsWithOptions is TranslateComponents configured by opts. Only NullableAsTypeArray is used.
+func TranslateComponentsWithOptions(components map[string]interface{}, opts Options) (map[string]interface{}, error) {
+	definitions := mapSchemaMapRefs(components, componentsRoot, definitionRef).(map[string]interface{})
+	t := &translation{opts: opts}
+	return mapSchemaMap(definitions, t.reverse), nil
+}
+
+// definitionRef points reference to component to the same definition
+func definitionRef(ref string) string {
+	name, rest, ok := splitRef(ref, componentsPrefix)
+	if !ok {
+		return ref
+	}
+	return definitionsPrefix + escapeRefToken(name) + rest
+}
+
+// reverse undoes translation of single schema: every pass of TranslateDefinitions which could be undone
+func (t *translation) reverse(schema map[string]interface{}) map[string]interface{} {
+	schema = numericExclusiveBounds(schema)
+	for extension, keyword := range map[string]string{
+		"x-comment":           "$comment",
+		"x-contentMediaType":  "contentMediaType",
+		"x-contentEncoding":   "contentEncoding",
+		"x-patternProperties": "patternProperties",
+	} {
+		if v, ok := schema[extension]; ok {
+			delete(schema, extension)
+			schema[keyword] = v
+		}
+	}
+	if patternProperties, ok := schema["patternProperties"].(map[string]interface{}); ok {
+		schema["patternProperties"] = mapSchemaMap(patternProperties, t.reverse)
+	}
+	if _, ok := schema["patternProperties"]; ok {
+		delete(schema, "additionalProperties")
+	}
+	if tuple, ok := schema["x-tuple"].(map[string]interface{}); ok {
+		delete(schema, "x-tuple")
+		for k, v := range tuple {
+			schema[k] = mapSchemas(v, t.reverse)
+		}
+	}
+	if example, ok := schema["example"]; ok {
+		delete(schema, "example")
+		more, _ := schema["x-examples"].([]interface{})
+		delete(schema, "x-examples")
+		schema["examples"] = append([]interface{}{example}, more...)
+	}
+	schema = undiscriminate(schema)
+	schema = reverseImplications(schema)
+	return t.reverseNullable(schema)
+}
+
+// undiscriminate turns oneOf with discriminator back into oneOf of conditions described for discriminate
+func undiscriminate(schema map[string]interface{}) map[string]interface{} {
+	discriminator, ok := schema["discriminator"].(map[string]interface{})
+	if !ok {
+		return schema
+	}
+	oneOf, ok := schema["oneOf"].([]interface{})
+	if !ok {
+		return schema
+	}
+	property, _ := discriminator["propertyName"].(string)
+	mapping, _ := discriminator["mapping"].(map[string]interface{})
+	values := make(map[string]string)
+	for value, ref := range mapping {
+		values[strings.Replace(toString(ref), componentsPrefix, definitionsPrefix, 1)] = value
+	}
+
+	cases := make([]interface{}, 0, len(oneOf))
+	for _, alternative := range oneOf {
+		ok, ref := getRef(alternative)
+		if !ok {
+			return schema
+		}
+		value, ok := values[ref]
+		if !ok {
+			name, _, ok := splitRef(ref, definitionsPrefix)
+			if !ok {
+				return schema
+			}
+			value = name // implicit mapping to schema name
+		}
+		condition := func() map[string]interface{} {
+			return map[string]interface{}{
+				"properties": map[string]interface{}{
+					property: map[string]interface{}{
+						"enum": []interface{}{value},
+					},
+				},
+			}
+		}
+		cases = append(cases, map[string]interface{}{
+			"if":   condition(),
+			"then": map[string]interface{}{"$ref": ref},
+			"else": condition(),
+		})
+	}
+	delete(schema, "discriminator")
+	schema["oneOf"] = cases
+	return schema
+}
+
+// reverseImplications turns anyOf made by materialImplication back into if, then and maybe else,
+// and anyOf made by translateDependencies back into dependencies
+func reverseImplications(schema map[string]interface{}) map[string]interface{} {
+	if anyOf, ok := schema["anyOf"].([]interface{}); ok && reverseImplication(schema, anyOf) {
+		delete(schema, "anyOf")
+	}
+	allOf, ok := schema["allOf"].([]interface{})
+	if !ok {
+		return schema
+	}
+	rest := make([]interface{}, 0, len(allOf))
+	for _, elem := range allOf {
+		// elements were already reversed on their own, so merge them if that's possible
+		obj, _ := elem.(map[string]interface{})
+		if mergeImplication(schema, obj) {
+			continue
+		}
+		rest = append(rest, elem)
+	}
+	switch {
+	case len(rest) == 0:
+		delete(schema, "allOf")
+	case schema["anyOf"] == nil && len(rest) == 1:
+		if obj, ok := rest[0].(map[string]interface{}); ok && len(obj) == 1 && obj["anyOf"] != nil {
+			delete(schema, "allOf")
+			schema["anyOf"] = obj["anyOf"]
+			break
+		}
+		schema["allOf"] = rest
+	default:
+		schema["allOf"] = rest
+	}
+	return schema
+}
+
+// mergeImplication moves condition or dependencies from obj to schema, if obj has nothing else
+func mergeImplication(schema, obj map[string]interface{}) bool {
+	if len(obj) == 0 {
+		return false
+	}
+	for k := range obj {
+		if k != "if" && k != "then" && k != "else" && k != "dependencies" {
+			return false
+		}
+	}
+	if _, ok := obj["if"]; ok && schema["if"] != nil {
+		return false
+	}
+	dependencies, _ := schema["dependencies"].(map[string]interface{})
+	objDependencies, _ := obj["dependencies"].(map[string]interface{})
+	for property := range objDependencies {
+		if _, ok := dependencies[property]; ok {
+			return false
+		}
+	}
+	for k, v := range obj {
+		if k != "dependencies" {
+			schema[k] = v
+		}
+	}
+	if len(objDependencies) > 0 {
+		if dependencies == nil {
+			dependencies = make(map[string]interface{})
+		}
+		for property, v := range objDependencies {
+			dependencies[property] = v
+		}
+		schema["dependencies"] = dependencies
+	}
+	return true
+}
+
+// reverseImplication puts condition or dependency expressed by anyOf into schema, and tells if it succeeded
+func reverseImplication(schema map[string]interface{}, anyOf []interface{}) bool {
+	if len(anyOf) != 2 {
+		return false
+	}
+	first, _ := anyOf[0].(map[string]interface{})
+	second, _ := anyOf[1].(map[string]interface{})
+
+	// { "allOf": [ CONDITION, SCHEMA1 ] }, { "allOf": [ {"not": CONDITION }, SCHEMA2 ] }
+	then, _ := first["allOf"].([]interface{})
+	els, _ := second["allOf"].([]interface{})
+	if len(first) == 1 && len(second) == 1 && len(then) == 2 && len(els) == 2 && schema["if"] == nil {
+		not, _ := els[0].(map[string]interface{})
+		if len(not) == 1 && reflect.DeepEqual(not["not"], then[0]) {
+			schema["if"] = then[0]
+			schema["then"] = then[1]
+			schema["else"] = els[1]
+			return true
+		}
+	}
+
+	// { "not": { "required": [ PROPERTY ] } }, CONSEQUENCE
+	not, _ := first["not"].(map[string]interface{})
+	if len(first) != 1 || not == nil {
+		return false
+	}
+	required, _ := not["required"].([]interface{})
+	property, ok := "", false
+	if len(not) == 1 && len(required) == 1 {
+		property, ok = required[0].(string)
+	}
+	if !ok {
+		// { "not": CONDITION }, SCHEMA1
+		if schema["if"] != nil {
+			return false
+		}
+		schema["if"] = not
+		schema["then"] = anyOf[1]
+		return true
+	}
+	dependencies, _ := schema["dependencies"].(map[string]interface{})
+	if dependencies == nil {
+		dependencies = make(map[string]interface{})
+	}
+	if _, ok := dependencies[property]; ok {
+		return false
+	}
+	consequence := anyOf[1]
+	if deps, ok := second["required"].([]interface{}); ok && len(second) == 1 && len(deps) > 0 && deps[0] == property {
+		consequence = deps[1:]
+	}
+	dependencies[property] = consequence
+	schema["dependencies"] = dependencies
+	return true
+}
+
+// reverseNullable replaces "type": X, "nullable": true with "oneOf": [{"type": X}, {"type": "null"}],
+// or with "type": [X, "null"] when Options.NullableAsTypeArray is set. Nullable schema without type,
+// which has keywords rejecting null like allOf or $ref, becomes "anyOf": [SCHEMA, {"type": "null"}].
+func (t *translation) reverseNullable(schema map[string]interface{}) map[string]interface{} {
+	nullable, _ := schema["nullable"].(bool)
+	delete(schema, "nullable")
+	if !nullable {
+		return schema
+	}
+	if enum, ok := schema["enum"].([]interface{}); ok {
+		schema["enum"] = append(append([]interface{}{}, enum...), nil)
+	}
+	typ, ok := schema["type"].(string)
+	if !ok {
+		return nullableComposition(schema)
+	}
+	if t.opts.NullableAsTypeArray {
+		schema["type"] = []interface{}{typ, "null"}
+		return schema
+	}
+	if _, ok := schema["oneOf"]; ok {
+		schema["type"] = []interface{}{typ, "null"}
+		return schema
+	}
+	delete(schema, "type")
+	schema["oneOf"] = []interface{}{
+		map[string]interface{}{"type": typ},
+		map[string]interface{}{"type": "null"},
+	}
+	return schema
+}
+
+// Keywords which could reject null in schema without type
+var nullRejectingKeywords = []string{"$ref", "allOf", "anyOf", "oneOf", "not"}
+
+// nullableComposition allows null for schema without type, if it has keywords which could reject it
+func nullableComposition(schema map[string]interface{}) map[string]interface{} {
+	for _, k := range nullRejectingKeywords {
+		if _, ok := schema[k]; ok {
+			return map[string]interface{}{
+				"anyOf": []interface{}{
+					schema,
+					map[string]interface{}{"type": "null"},
+				},
+			}
+		}
+	}
+	return schema
+}
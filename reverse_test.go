@@ -0,0 +1,145 @@
+package jsonschema2openapi
+
+import (
+	"encoding/json"
+
+	"github.com/bunyk/jsonschema2openapi/fixtures"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+// expectRoundTrip checks that definitions translated to OpenAPI and back are the same as they were
+func expectRoundTrip(schemaJSON string) {
+	var schema map[string]interface{}
+	Expect(json.Unmarshal([]byte(schemaJSON), &schema)).To(Succeed())
+	definitions := mapSchemaMapRefs(schema["definitions"], definitionsPrefix, definitionRef).(map[string]interface{})
+
+	res, err := json.Marshal(TranslateComponents(TranslateDefinitions(definitions)))
+	Expect(err).To(BeNil())
+	expected, err := json.Marshal(definitions)
+	Expect(err).To(BeNil())
+	Expect(res).To(MatchJSON(expected))
+}
+
+var _ = Describe("TranslateComponents", func() {
+	It("should round trip discriminator fixture", func() {
+		expectRoundTrip(fixtures.DiscriminatorJSON)
+	})
+
+	It("should round trip conditions and nullable types", func() {
+		expectRoundTrip(`{
+			"definitions": {
+				"Data": {
+					"oneOf": [
+						{
+							"if": { "properties": { "type": { "enum": [ "stringornull" ] } } },
+							"then": { "properties": {"payload": {"$ref": "#/definitions/Payload1" } } },
+							"else": { "properties": { "type": { "enum": [ "stringornull" ] } } }
+						},
+						{
+							"if": { "properties": { "type": { "enum": [ "int" ] } } },
+							"then": { "properties": {"payload": {"$ref": "#/definitions/Payload2" } } },
+							"else": { "properties": { "type": { "enum": [ "int" ] } } }
+						}
+					]
+				},
+				"Payload1": { "oneOf": [ { "type": "string" }, { "type": "null" } ] },
+				"Payload2": { "type": "integer", "exclusiveMinimum": 0, "examples": [1, 2] }
+			}
+		}`)
+	})
+
+	It("should round trip dependencies", func() {
+		expectRoundTrip(`{
+			"definitions": {
+				"Card": {
+					"anyOf": [ { "required": ["number"] }, { "required": ["token"] } ],
+					"dependencies": {
+						"number": ["expiry"],
+						"token": { "properties": { "provider": { "type": "string" } } }
+					}
+				}
+			}
+		}`)
+	})
+
+	It("should round trip conditions without else", func() {
+		expectRoundTrip(`{
+			"definitions": {
+				"Card": {
+					"if": { "properties": { "kind": { "enum": [ "credit" ] } } },
+					"then": { "required": [ "limit" ] },
+					"dependencies": { "number": [ "expiry" ] }
+				}
+			}
+		}`)
+	})
+
+	It("should use type arrays for nullable when asked", func() {
+		res, err := TranslateComponentsWithOptions(map[string]interface{}{
+			"Name": map[string]interface{}{"type": "string", "nullable": true},
+		}, Options{NullableAsTypeArray: true})
+		Expect(err).To(BeNil())
+		Expect(res).To(Equal(map[string]interface{}{
+			"Name": map[string]interface{}{"type": []interface{}{"string", "null"}},
+		}))
+	})
+
+	It("should extract JSON Schema from OpenAPI spec", func() {
+		schema, err := ExtractSchemaFromOpenAPI(`{
+			"components": { "schemas": {
+				"Names": { "type": "array", "items": { "$ref": "#/components/schemas/Name" } },
+				"Name": { "type": "string" }
+			} }
+		}`)
+		Expect(err).To(BeNil())
+		Expect(schema).To(MatchJSON(`{
+			"$schema": "http://json-schema.org/draft-07/schema#",
+			"definitions": {
+				"Names": { "type": "array", "items": { "$ref": "#/definitions/Name" } },
+				"Name": { "type": "string" }
+			}
+		}`))
+	})
+
+	It("should not mistake properties named $ref for references, nor rewrite references in examples", func() {
+		res := TranslateComponents(map[string]interface{}{
+			"Link": map[string]interface{}{
+				"properties": map[string]interface{}{
+					"$ref":   map[string]interface{}{"type": "string"},
+					"target": map[string]interface{}{"$ref": "#/components/schemas/Name"},
+				},
+				"example": map[string]interface{}{"$ref": "#/components/schemas/Name"},
+			},
+		})
+		Expect(res).To(Equal(map[string]interface{}{
+			"Link": map[string]interface{}{
+				"properties": map[string]interface{}{
+					"$ref":   map[string]interface{}{"type": "string"},
+					"target": map[string]interface{}{"$ref": "#/definitions/Name"},
+				},
+				"examples": []interface{}{map[string]interface{}{"$ref": "#/components/schemas/Name"}},
+			},
+		}))
+	})
+
+	It("should keep null allowed in nullable schemas without type", func() {
+		res := TranslateComponents(map[string]interface{}{
+			"Owner": map[string]interface{}{
+				"allOf":    []interface{}{map[string]interface{}{"$ref": "#/components/schemas/Person"}},
+				"nullable": true,
+			},
+			"Any": map[string]interface{}{"description": "anything", "nullable": true},
+		})
+		Expect(res).To(Equal(map[string]interface{}{
+			"Owner": map[string]interface{}{
+				"anyOf": []interface{}{
+					map[string]interface{}{"allOf": []interface{}{map[string]interface{}{"$ref": "#/definitions/Person"}}},
+					map[string]interface{}{"type": "null"},
+				},
+			},
+			"Any": map[string]interface{}{"description": "anything"},
+		}))
+	})
+})
@@ -0,0 +1,76 @@
+package jsonschema2openapi
+
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
+// collectDefinitions returns definitions of JSON schema, with root schema added to them when opts.IncludeRoot is set
+func collectDefinitions(schema map[string]interface{}, opts Options) (map[string]interface{}, error) {
+	definitions, _ := schema["definitions"].(map[string]interface{})
+	if !opts.IncludeRoot {
+		return definitions, nil
+	}
+	name := rootName(schema, opts)
+	if name == "" {
+		return nil, fmt.Errorf("Not able to name root schema, it has no title or $id, and Options.RootName is empty")
+	}
+	if _, ok := definitions[name]; ok {
+		return nil, fmt.Errorf("Root schema name %q is already used by definition", name)
+	}
+
+	root := make(map[string]interface{})
+	for k, v := range schema {
+		if k != "definitions" {
+			root[k] = v
+		}
+	}
+	res := map[string]interface{}{
+		name: root,
+	}
+	for k, v := range definitions {
+		res[k] = v
+	}
+	return rootRefs(res, definitionsPrefix+escapeRefToken(name)).(map[string]interface{}), nil
+}
+
+// rootName returns name for root schema component: explicit from options, title or last part of $id
+func rootName(schema map[string]interface{}, opts Options) string {
+	if opts.RootName != "" {
+		return opts.RootName
+	}
+	if title, ok := schema["title"].(string); ok && title != "" {
+		return title
+	}
+	if id, ok := schema["$id"].(string); ok {
+		id = strings.TrimRight(strings.SplitN(id, "#", 2)[0], "/")
+		name := path.Base(id)
+		name = strings.TrimSuffix(name, path.Ext(name))
+		if name != "." && name != "/" {
+			return name
+		}
+	}
+	return ""
+}
+
+// isRootRef checks if reference points into root schema, and not into definitions or components
+func isRootRef(ref string) bool {
+	if ref == "#" {
+		return true
+	}
+	return strings.HasPrefix(ref, "#/") &&
+		!strings.HasPrefix(ref, definitionsPrefix) &&
+		!strings.HasPrefix(ref, componentsPrefix)
+}
+
+// rootRefs replaces references to root schema like "#" or "#/properties/a" in schemas with references to rootRef.
+// References to definitions, and references inside examples, defaults, enums and extensions are left as they are.
+func rootRefs(schemas map[string]interface{}, rootRef string) interface{} {
+	return mapSchemaMapRefs(schemas, "#/definitions", func(ref string) string {
+		if isRootRef(ref) {
+			return rootRef + ref[1:]
+		}
+		return ref
+	})
+}
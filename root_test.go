@@ -0,0 +1,90 @@
+package jsonschema2openapi
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var rootSchema = `{
+	"$id": "https://example.com/schemas/tree.json",
+	"type": "object",
+	"properties": {
+		"children": {
+			"type": "array",
+			"items": { "$ref": "#" }
+		},
+		"leaf": { "$ref": "#/definitions/Leaf" },
+		"label": { "$ref": "#/properties/leaf" }
+	},
+	"definitions": {
+		"Leaf": { "type": "string" }
+	}
+}`
+
+var _ = Describe("Root schema", func() {
+	It("should not be translated by default", func() {
+		api, err := PutSchemaIntoOpenAPI(rootSchema, minOpenAPI)
+		Expect(err).To(BeNil())
+
+		schemas, err := Jq(api).Object("components", "schemas")
+		Expect(err).To(BeNil())
+		Expect(schemas).To(HaveLen(2))
+		Expect(schemas).To(HaveKey("Leaf"))
+	})
+
+	It("should be named by $id and referenced instead of #", func() {
+		api, err := PutSchemaIntoOpenAPIWithOptions(rootSchema, minOpenAPI, Options{IncludeRoot: true})
+		Expect(err).To(BeNil())
+
+		jq := Jq(api)
+		Expect(jq.String("components", "schemas", "tree", "type")).To(Equal("object"))
+		Expect(jq.String("components", "schemas", "tree", "properties", "children", "items", "$ref")).To(
+			Equal("#/components/schemas/tree"),
+		)
+		Expect(jq.String("components", "schemas", "tree", "properties", "leaf", "$ref")).To(
+			Equal("#/components/schemas/Leaf"),
+		)
+		Expect(jq.String("components", "schemas", "tree", "properties", "label", "$ref")).To(
+			Equal("#/components/schemas/tree/properties/leaf"),
+		)
+		_, err = jq.Object("components", "schemas", "tree", "definitions")
+		Expect(err).NotTo(BeNil())
+	})
+
+	It("should prefer explicit name", func() {
+		api, err := PutSchemaIntoOpenAPIWithOptions(rootSchema, minOpenAPI, Options{
+			IncludeRoot: true,
+			RootName:    "Tree",
+		})
+		Expect(err).To(BeNil())
+		Expect(Jq(api).String("components", "schemas", "Tree", "properties", "children", "items", "$ref")).To(
+			Equal("#/components/schemas/Tree"),
+		)
+	})
+
+	It("should fail when root could not be named", func() {
+		_, err := PutSchemaIntoOpenAPIWithOptions(`{"type": "string"}`, minOpenAPI, Options{IncludeRoot: true})
+		Expect(err).NotTo(BeNil())
+	})
+
+	It("should not rewrite references to root inside data and extensions", func() {
+		api, err := PutSchemaIntoOpenAPIWithOptions(`{
+			"title": "Node",
+			"properties": {
+				"next": { "$ref": "#" },
+				"a": {
+					"default": { "$ref": "#" },
+					"enum": [{ "$ref": "#/properties/a" }],
+					"x-see": { "$ref": "#" }
+				}
+			}
+		}`, minOpenAPI, Options{IncludeRoot: true})
+		Expect(err).To(BeNil())
+
+		jq := Jq(api)
+		Expect(jq.String("components", "schemas", "Node", "properties", "next", "$ref")).To(Equal("#/components/schemas/Node"))
+		Expect(jq.String("components", "schemas", "Node", "properties", "a", "default", "$ref")).To(Equal("#"))
+		Expect(jq.String("components", "schemas", "Node", "properties", "a", "enum", "0", "$ref")).To(Equal("#/properties/a"))
+		Expect(jq.String("components", "schemas", "Node", "properties", "a", "x-see", "$ref")).To(Equal("#"))
+	})
+})
@@ -0,0 +1,596 @@
+package jsonschema2openapi
+
+import (
+	"hash/fnv"
+	"math"
+	"math/big"
+	"math/rand"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/bunyk/jsonschema2openapi/validator"
+)
+
+// Deeper than that only required properties and minimal arrays are generated, and nullable schemas are null.
+// Optional properties, items and alternatives referencing components which are already being expanded
+// are left out from there on, and if such reference could not be left out, it gives null.
+const maxSampleDepth = 4
+
+// Sample values for string formats
+var formatSamples = map[string]string{
+	"date":      "2020-01-01",
+	"date-time": "2020-01-01T00:00:00Z",
+	"time":      "12:00:00",
+	"email":     "user@example.com",
+	"hostname":  "example.com",
+	"ipv4":      "192.0.2.1",
+	"ipv6":      "2001:db8::1",
+	"uri":       "https://example.com",
+	"url":       "https://example.com",
+	"uuid":      "3fa85f64-5717-4562-b3fc-2c963f66afa6",
+	"byte":      "U3dhZ2dlciByb2Nrcw==",
+	"binary":    "binary",
+	"password":  "secret",
+}
+
+const sampleLetters = "abcdefghijklmnopqrstuvwxyz"
+
+// Number of instances generated for schema until one of them is valid
+const maxSampleAttempts = 20
+
+// GenerateExample synthesises instance valid against OpenAPI schema. References are resolved in components,
+// which are contents of components/schemas. The same seed always gives the same instance.
+// Given example, default and enum values are preferred, formats, bounds, required properties,
+// nullable and discriminator mappings are respected. Patterns and "not" are not, but instances are generated
+// until one is valid against schema. If none is, the last of them is returned.
+func GenerateExample(schema interface{}, components map[string]interface{}, seed int64) interface{} {
+	example, _ := generateExample(schema, components, seed)
+	return example
+}
+
+// generateExample returns instance generated for schema, and tells if it is valid
+func generateExample(schema interface{}, components map[string]interface{}, seed int64) (interface{}, bool) {
+	s := sampler{
+		components: components,
+		rand:       rand.New(rand.NewSource(seed)),
+		expanding:  make(map[string]int),
+	}
+	var example interface{}
+	for i := 0; i < maxSampleAttempts; i++ {
+		example = s.sample(schema, 0)
+		if s.valid(schema, example) {
+			return example, true
+		}
+	}
+	return example, false
+}
+
+// generateExamples sets "example" of every component which has none. Component is left without example,
+// when no valid instance of it could be generated.
+func (t *translation) generateExamples(components map[string]interface{}) map[string]interface{} {
+	names := make([]string, 0, len(components))
+	for name := range components {
+		names = append(names, name)
+	}
+	sort.Strings(names) // so diagnostics are reported in the same order every time
+	res := make(map[string]interface{})
+	for _, name := range names {
+		c := components[name]
+		res[name] = c
+		schema, ok := c.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if _, ok := schema["example"]; ok {
+			continue
+		}
+		if _, ok := schema["$ref"]; ok {
+			continue
+		}
+		h := fnv.New64a()
+		h.Write([]byte(name))
+		example, valid := generateExample(schema, components, t.opts.ExampleSeed^int64(h.Sum64()))
+		if !valid {
+			t.report(componentsPrefix+escapeRefToken(name), "not able to generate valid example")
+			continue
+		}
+		copied := make(map[string]interface{})
+		for k, v := range schema {
+			copied[k] = v
+		}
+		copied["example"] = example
+		res[name] = copied
+	}
+	return res
+}
+
+type sampler struct {
+	components map[string]interface{}
+	rand       *rand.Rand
+	// expanding counts references to components which are being sampled
+	expanding map[string]int
+}
+
+func (s *sampler) sample(schema interface{}, depth int) interface{} {
+	obj, ok := schema.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	if ref, ok := obj["$ref"].(string); ok {
+		name, rest, ok := splitRef(ref, componentsPrefix)
+		if !ok || rest != "" || s.stops(obj, depth) {
+			return nil
+		}
+		s.expanding[name]++
+		res := s.sample(s.components[name], depth+1)
+		s.expanding[name]--
+		return res
+	}
+	if example, ok := obj["example"]; ok {
+		return example
+	}
+	if def, ok := obj["default"]; ok {
+		return def
+	}
+	if enum, ok := obj["enum"].([]interface{}); ok && len(enum) > 0 {
+		return enum[s.rand.Intn(len(enum))]
+	}
+	if obj["nullable"] == true && depth >= maxSampleDepth {
+		return nil
+	}
+	if discriminator, ok := obj["discriminator"].(map[string]interface{}); ok {
+		if res, ok := s.discriminated(obj, discriminator, depth); ok {
+			return res
+		}
+	}
+	for _, keyword := range []string{"oneOf", "anyOf"} {
+		if alternatives, ok := obj[keyword].([]interface{}); ok && len(alternatives) > 0 {
+			if finite := s.finite(alternatives, depth+1); len(finite) > 0 {
+				alternatives = finite
+			}
+			// alternative is sampled together with the rest of schema, and alternatives are tried in random order
+			// until sample is valid, as sampler does not respect "not" in conditions and dependencies
+			own := without(obj, keyword, "discriminator")
+			var res interface{}
+			for _, i := range s.rand.Perm(len(alternatives)) {
+				res = s.mergedWith(own, alternatives[i], depth)
+				if s.valid(obj, res) {
+					break
+				}
+			}
+			return res
+		}
+	}
+	if allOf, ok := obj["allOf"].([]interface{}); ok {
+		return s.merged(obj, allOf, depth)
+	}
+
+	switch schemaType(obj) {
+	case "string":
+		return s.string(obj)
+	case "integer":
+		return s.number(obj, true)
+	case "number":
+		return s.number(obj, false)
+	case "boolean":
+		return s.rand.Intn(2) == 1
+	case "array":
+		return s.array(obj, depth)
+	case "object":
+		return s.object(obj, depth)
+	}
+	if obj["nullable"] == true {
+		return nil
+	}
+	return map[string]interface{}{}
+}
+
+// stops checks if sample of schema at depth would be cut off, because schema, or one of its subschemas
+// which could not be left out, references component which is already being expanded
+func (s *sampler) stops(schema interface{}, depth int) bool {
+	obj, _ := schema.(map[string]interface{})
+	if ref, ok := obj["$ref"].(string); ok {
+		name, rest, ok := splitRef(ref, componentsPrefix)
+		return ok && rest == "" && s.expanding[name] > 0 && depth >= maxSampleDepth
+	}
+	if obj["nullable"] == true && depth >= maxSampleDepth {
+		return false
+	}
+	allOf, _ := obj["allOf"].([]interface{})
+	for _, sub := range allOf {
+		if s.stops(sub, depth+1) {
+			return true
+		}
+	}
+	if minItems, ok := toFloat(obj["minItems"]); ok && minItems > 0 && s.stops(obj["items"], depth+1) {
+		return true
+	}
+	properties, _ := obj["properties"].(map[string]interface{})
+	required, _ := obj["required"].([]interface{})
+	for _, name := range required {
+		if name, ok := name.(string); ok && s.stops(properties[name], depth+1) {
+			return true
+		}
+	}
+	return false
+}
+
+// finite returns schemas which samples at depth would not be cut off
+func (s *sampler) finite(schemas []interface{}, depth int) []interface{} {
+	var res []interface{}
+	for _, schema := range schemas {
+		if !s.stops(schema, depth) {
+			res = append(res, schema)
+		}
+	}
+	return res
+}
+
+// schemaType returns type of schema, guessing it from keywords when there is no "type"
+func schemaType(schema map[string]interface{}) string {
+	if t, ok := schema["type"].(string); ok {
+		return t
+	}
+	switch {
+	case schema["properties"] != nil || schema["required"] != nil || schema["additionalProperties"] != nil:
+		return "object"
+	case schema["items"] != nil:
+		return "array"
+	case schema["format"] != nil || schema["pattern"] != nil || schema["minLength"] != nil || schema["maxLength"] != nil:
+		return "string"
+	case schema["minimum"] != nil || schema["maximum"] != nil || schema["multipleOf"] != nil:
+		return "number"
+	}
+	return ""
+}
+
+func (s *sampler) string(schema map[string]interface{}) interface{} {
+	minLength, hasMin := toFloat(schema["minLength"])
+	maxLength, hasMax := toFloat(schema["maxLength"])
+	if format, ok := schema["format"].(string); ok {
+		// format sample is used only when it fits length bounds and pattern, otherwise letters are generated
+		sample, ok := formatSamples[format]
+		length := float64(utf8.RuneCountInString(sample))
+		if ok && (!hasMin || length >= minLength) && (!hasMax || length <= maxLength) && matchesPattern(schema, sample) {
+			return sample
+		}
+	}
+	min, max := 3, 10
+	if hasMin {
+		min = int(minLength)
+		if max < min {
+			max = min
+		}
+	}
+	if hasMax {
+		max = int(maxLength)
+		if min > max {
+			min = max
+		}
+	}
+	length := min
+	if max > min {
+		length += s.rand.Intn(max - min + 1)
+	}
+	var b strings.Builder
+	for i := 0; i < length; i++ {
+		b.WriteByte(sampleLetters[s.rand.Intn(len(sampleLetters))])
+	}
+	return b.String()
+}
+
+// matchesPattern checks if value matches pattern of schema, if it has one
+func matchesPattern(schema map[string]interface{}, value string) bool {
+	pattern, ok := schema["pattern"].(string)
+	if !ok {
+		return true
+	}
+	matched, err := regexp.MatchString(pattern, value)
+	return err == nil && matched
+}
+
+// number samples multiple of multipleOf, or of 1 for integers and of 0.5 for other numbers, within bounds.
+// When no such multiple fits between bounds, middle of them is given.
+func (s *sampler) number(schema map[string]interface{}, integer bool) float64 {
+	step := 1.0
+	if !integer {
+		step = 0.5
+	}
+	if m, ok := toFloat(schema["multipleOf"]); ok && m > 0 {
+		step = m
+		if integer {
+			step = integerMultiple(m)
+		}
+	}
+	min, hasMin := toFloat(schema["minimum"])
+	max, hasMax := toFloat(schema["maximum"])
+	exclusiveMin := hasMin && schema["exclusiveMinimum"] == true
+	exclusiveMax := hasMax && schema["exclusiveMaximum"] == true
+	switch {
+	case hasMin && hasMax:
+	case hasMin:
+		max = min + 100*step
+	case hasMax:
+		min = max - 100*step
+		if min < 0 && (max > 0 || max == 0 && !exclusiveMax) {
+			min = 0
+		}
+	default:
+		min, max = 0, 100*step
+	}
+
+	first := math.Ceil(min / step)
+	if exclusiveMin && first*step <= min {
+		first++
+	}
+	last := math.Floor(max / step)
+	if exclusiveMax && last*step >= max {
+		last--
+	}
+	if first > last {
+		return (min + max) / 2
+	}
+	steps := last - first
+	if steps > math.MaxInt32 {
+		steps = math.MaxInt32
+	}
+	return (first + float64(s.rand.Intn(int(steps)+1))) * step
+}
+
+// integerMultiple returns least common multiple of m and 1, which is m divided by greatest common divisor
+// of its decimal digits and power of ten it is scaled by
+func integerMultiple(m float64) float64 {
+	text := strconv.FormatFloat(m, 'f', -1, 64)
+	decimals := 0
+	if i := strings.IndexByte(text, '.'); i >= 0 {
+		decimals = len(text) - i - 1
+	}
+	scale := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(decimals)), nil)
+	n, ok := new(big.Int).SetString(strings.Replace(text, ".", "", 1), 10)
+	if !ok || n.Sign() <= 0 {
+		return math.Ceil(m)
+	}
+	n.Quo(n, new(big.Int).GCD(nil, nil, n, scale))
+	res, _ := new(big.Float).SetInt(n).Float64()
+	return res
+}
+
+func (s *sampler) array(schema map[string]interface{}, depth int) interface{} {
+	min, max := 1, 2
+	if depth >= maxSampleDepth {
+		min, max = 0, 0
+	}
+	if n, ok := toFloat(schema["minItems"]); ok {
+		min = int(n)
+		if max < min {
+			max = min
+		}
+	}
+	if n, ok := toFloat(schema["maxItems"]); ok && int(n) < max {
+		max = int(n)
+	}
+	if s.stops(schema["items"], depth+1) {
+		max = min
+	}
+	length := min
+	if max > min {
+		length += s.rand.Intn(max - min + 1)
+	}
+	res := make([]interface{}, 0, length)
+	for attempts := 0; len(res) < length && attempts < length*10; attempts++ {
+		item := s.sample(schema["items"], depth+1)
+		if schema["uniqueItems"] == true && containsJSON(res, item) {
+			continue
+		}
+		res = append(res, item)
+	}
+	return res
+}
+
+func (s *sampler) object(schema map[string]interface{}, depth int) interface{} {
+	res := make(map[string]interface{})
+	properties, _ := schema["properties"].(map[string]interface{})
+	required := make(map[string]bool)
+	var requiredNames []string
+	if r, ok := schema["required"].([]interface{}); ok {
+		for _, name := range r {
+			if n, ok := name.(string); ok {
+				required[n] = true
+				requiredNames = append(requiredNames, n)
+			}
+		}
+	}
+	names := make([]string, 0, len(properties))
+	for name := range properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		p, _ := properties[name].(map[string]interface{})
+		optional := !required[name]
+		if optional && (depth >= maxSampleDepth || p["readOnly"] == true || p["writeOnly"] == true || s.stops(p, depth+1)) {
+			continue
+		}
+		res[name] = s.sample(p, depth+1)
+	}
+	for _, name := range requiredNames {
+		if _, ok := res[name]; ok {
+			continue
+		}
+		if additional, ok := schema["additionalProperties"].(map[string]interface{}); ok && len(additional) > 0 {
+			res[name] = s.sample(additional, depth+1)
+		} else {
+			// any value is allowed, but null would not be valid in OpenAPI
+			res[name] = s.string(map[string]interface{}{})
+		}
+	}
+	return res
+}
+
+// merged combines samples of every schema in allOf, together with own keywords of schema. Schemas without
+// references are merged with own keywords before sampling, samples of referenced schemas, and of schemas
+// with alternatives when there are already alternatives to choose from, are merged after.
+func (s *sampler) merged(schema map[string]interface{}, allOf []interface{}, depth int) interface{} {
+	own := without(schema, "allOf")
+	var refs []interface{}
+	for _, sub := range allOf {
+		obj, ok := sub.(map[string]interface{})
+		switch {
+		case !ok:
+		case obj["$ref"] != nil || hasAlternatives(own) && hasAlternatives(obj):
+			refs = append(refs, obj)
+		default:
+			own = mergeSchemas(own, obj)
+		}
+	}
+	res := s.sample(own, depth+1)
+	for _, sub := range refs {
+		sample := s.sample(sub, depth+1)
+		obj, ok := sample.(map[string]interface{})
+		if !ok {
+			if res == nil {
+				res = sample
+			}
+			continue
+		}
+		merged := make(map[string]interface{})
+		if previous, ok := res.(map[string]interface{}); ok {
+			for k, v := range previous {
+				merged[k] = v
+			}
+		}
+		for k, v := range obj {
+			if _, ok := merged[k]; !ok {
+				merged[k] = v
+			}
+		}
+		res = merged
+	}
+	return res
+}
+
+// mergedWith is merged of schema and its allOf together with sub
+func (s *sampler) mergedWith(schema map[string]interface{}, sub interface{}, depth int) interface{} {
+	allOf, _ := schema["allOf"].([]interface{})
+	return s.merged(schema, append(append([]interface{}{}, allOf...), sub), depth)
+}
+
+// mergeSchemas returns schema which values match both a and b, as far as sampler is concerned:
+// properties and required are joined, enums intersected, and other keywords of a take precedence
+func mergeSchemas(a, b map[string]interface{}) map[string]interface{} {
+	if a["$ref"] != nil || b["$ref"] != nil {
+		return map[string]interface{}{"allOf": []interface{}{a, b}}
+	}
+	res := without(a)
+	for k, v := range b {
+		existing, ok := res[k]
+		if !ok {
+			res[k] = v
+			continue
+		}
+		switch k {
+		case "properties":
+			aProperties, _ := existing.(map[string]interface{})
+			bProperties, _ := v.(map[string]interface{})
+			properties := without(aProperties)
+			for name, p := range bProperties {
+				aProperty, aOK := properties[name].(map[string]interface{})
+				bProperty, bOK := p.(map[string]interface{})
+				switch {
+				case !aOK:
+					properties[name] = p
+				case bOK:
+					properties[name] = mergeSchemas(aProperty, bProperty)
+				}
+			}
+			res[k] = properties
+		case "required":
+			aRequired, _ := existing.([]interface{})
+			bRequired, _ := v.([]interface{})
+			res[k] = append(append([]interface{}{}, aRequired...), missing(bRequired, aRequired)...)
+		case "enum":
+			aEnum, _ := existing.([]interface{})
+			bEnum, _ := v.([]interface{})
+			if common := missing(aEnum, missing(aEnum, bEnum)); len(common) > 0 {
+				res[k] = common
+			}
+		case "allOf":
+			aAllOf, _ := existing.([]interface{})
+			bAllOf, _ := v.([]interface{})
+			res[k] = append(append([]interface{}{}, aAllOf...), bAllOf...)
+		}
+	}
+	return res
+}
+
+func hasAlternatives(schema map[string]interface{}) bool {
+	return schema["oneOf"] != nil || schema["anyOf"] != nil
+}
+
+// without returns copy of schema without keywords
+func without(schema map[string]interface{}, keywords ...string) map[string]interface{} {
+	res := make(map[string]interface{}, len(schema))
+	for k, v := range schema {
+		res[k] = v
+	}
+	for _, k := range keywords {
+		delete(res, k)
+	}
+	return res
+}
+
+// discriminated picks one of discriminator mappings and generates sample of it, together with the rest of schema
+func (s *sampler) discriminated(schema, discriminator map[string]interface{}, depth int) (interface{}, bool) {
+	property, _ := discriminator["propertyName"].(string)
+	mapping, _ := discriminator["mapping"].(map[string]interface{})
+	if len(mapping) == 0 {
+		return nil, false
+	}
+	values := make([]string, 0, len(mapping))
+	for v := range mapping {
+		values = append(values, v)
+	}
+	sort.Strings(values)
+	own := without(schema, "oneOf", "anyOf", "discriminator")
+	var res map[string]interface{}
+	for _, i := range s.rand.Perm(len(values)) {
+		sample, ok := s.mergedWith(own, map[string]interface{}{"$ref": mapping[values[i]]}, depth).(map[string]interface{})
+		if !ok {
+			continue
+		}
+		res = map[string]interface{}{
+			property: values[i],
+		}
+		for k, v := range sample {
+			if k != property {
+				res[k] = v
+			}
+		}
+		if s.valid(schema, res) {
+			break
+		}
+	}
+	return res, res != nil
+}
+
+// valid checks if instance is valid against schema, which references components
+func (s *sampler) valid(schema interface{}, instance interface{}) bool {
+	v := validator.New(map[string]interface{}{
+		"components": map[string]interface{}{"schemas": s.components},
+		"schema":     schema,
+	}, validator.OpenAPI30)
+	return len(v.ValidateAt("#/schema", instance)) == 0
+}
+
+func containsJSON(values []interface{}, value interface{}) bool {
+	for _, v := range values {
+		if reflect.DeepEqual(v, value) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,264 @@
+package jsonschema2openapi
+
+import (
+	"encoding/json"
+
+	"github.com/bunyk/jsonschema2openapi/fixtures"
+	"github.com/bunyk/jsonschema2openapi/validator"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var sampleDefinitions = `{
+	"Size": { "enum": ["S", "M", "L"] },
+	"Cat": {
+		"type": "object",
+		"properties": {
+			"kind": { "type": "string" },
+			"name": { "type": "string", "minLength": 2, "maxLength": 4 },
+			"born": { "type": "string", "format": "date" },
+			"size": { "$ref": "#/definitions/Size" }
+		},
+		"required": ["kind", "name"]
+	},
+	"Dog": {
+		"type": "object",
+		"properties": {
+			"kind": { "type": "string" },
+			"weight": { "type": "number", "exclusiveMinimum": 0, "maximum": 80, "multipleOf": 0.5 },
+			"owner": { "oneOf": [ { "type": "string", "format": "email" }, { "type": "null" } ] },
+			"tags": { "type": "array", "items": { "type": "integer", "minimum": 1 }, "minItems": 2, "uniqueItems": true }
+		},
+		"required": ["kind", "weight", "tags"]
+	},
+	"Pet": {
+		"oneOf": [
+			{
+				"if": { "properties": { "kind": { "enum": [ "cat" ] } } },
+				"then": { "$ref": "#/definitions/Cat" },
+				"else": { "properties": { "kind": { "enum": [ "cat" ] } } }
+			},
+			{
+				"if": { "properties": { "kind": { "enum": [ "dog" ] } } },
+				"then": { "$ref": "#/definitions/Dog" },
+				"else": { "properties": { "kind": { "enum": [ "dog" ] } } }
+			}
+		]
+	},
+	"Named": { "type": "string", "example": "Rex" }
+}`
+
+func translatedSamples(seed int64) map[string]interface{} {
+	var definitions map[string]interface{}
+	Expect(json.Unmarshal([]byte(sampleDefinitions), &definitions)).To(Succeed())
+	res, err := TranslateDefinitionsWithOptions(definitions, Options{GenerateExamples: true, ExampleSeed: seed})
+	Expect(err).To(BeNil())
+
+	// normalize types the way they would be after output
+	data, err := json.Marshal(res)
+	Expect(err).To(BeNil())
+	var components map[string]interface{}
+	Expect(json.Unmarshal(data, &components)).To(Succeed())
+	return components
+}
+
+var _ = Describe("Generated examples", func() {
+	It("should be valid against their schemas", func() {
+		for seed := int64(0); seed < 20; seed++ {
+			components := translatedSamples(seed)
+			v := validator.New(map[string]interface{}{
+				"components": map[string]interface{}{"schemas": components},
+			}, validator.OpenAPI30)
+			for name, c := range components {
+				example := c.(map[string]interface{})["example"]
+				Expect(v.ValidateAt(componentsPrefix+name, example)).To(BeEmpty(), "example of %s with seed %d", name, seed)
+			}
+		}
+	})
+
+	It("should be reproducible", func() {
+		Expect(translatedSamples(42)).To(Equal(translatedSamples(42)))
+	})
+
+	It("should not replace given example", func() {
+		Expect(translatedSamples(1)["Named"]).To(HaveKeyWithValue("example", "Rex"))
+	})
+
+	It("should follow discriminator mapping", func() {
+		example := GenerateExample(
+			translatedSamples(0)["Pet"],
+			translatedSamples(0),
+			7,
+		).(map[string]interface{})
+		Expect(example["kind"]).To(BeElementOf("cat", "dog"))
+		if example["kind"] == "cat" {
+			Expect(example).To(HaveKey("name"))
+		} else {
+			Expect(example).To(HaveKey("weight"))
+		}
+	})
+
+	It("should stop on recursive required references", func() {
+		for _, definitions := range []string{
+			`{"L": {"type": "object", "required": ["next"], "properties": {"next": {"$ref": "#/definitions/L"}}}}`,
+			`{"L": {"type": "object", "required": ["next"], "properties": {"next": {"nullable": true, "allOf": [{"$ref": "#/definitions/L"}]}}}}`,
+			`{"L": {"allOf": [{"$ref": "#/definitions/M"}]}, "M": {"oneOf": [{"$ref": "#/definitions/L"}]}}`,
+		} {
+			components := TranslateDefinitions(decodeDefinitions(`{"definitions": ` + definitions + `}`))
+			example := GenerateExample(components["L"], components, 0)
+			if list, ok := example.(map[string]interface{}); ok {
+				depth := 0
+				for list != nil {
+					list, _ = list["next"].(map[string]interface{})
+					depth++
+				}
+				Expect(depth).To(BeNumerically("<=", maxSampleDepth+1))
+			}
+		}
+	})
+
+	It("should end recursion of nullable schema with null", func() {
+		components := TranslateDefinitions(decodeDefinitions(`{"definitions": {
+			"L": {"type": "object", "required": ["next"], "properties": {"next": {"nullable": true, "allOf": [{"$ref": "#/definitions/L"}]}}}
+		}}`))
+		v := validator.New(map[string]interface{}{
+			"components": map[string]interface{}{"schemas": components},
+		}, validator.OpenAPI30)
+		example := GenerateExample(components["L"], components, 0)
+		data, err := json.Marshal(example)
+		Expect(err).To(BeNil())
+		var normalized interface{}
+		Expect(json.Unmarshal(data, &normalized)).To(Succeed())
+		Expect(v.ValidateAt(componentsPrefix+"L", normalized)).To(BeEmpty())
+	})
+
+	It("should leave out optional recursive references instead of making them null", func() {
+		components := TranslateDefinitions(decodeDefinitions(`{"definitions": {
+			"Node": {"type": "object", "properties": {
+				"next": {"$ref": "#/definitions/Node"},
+				"children": {"type": "array", "minItems": 1, "items": {"$ref": "#/definitions/Node"}},
+				"either": {"oneOf": [{"$ref": "#/definitions/Node"}, {"type": "string"}]}
+			}}
+		}}`))
+		v := validator.New(map[string]interface{}{
+			"components": map[string]interface{}{"schemas": components},
+		}, validator.OpenAPI30)
+		for seed := int64(0); seed < 20; seed++ {
+			example := GenerateExample(components["Node"], components, seed)
+			data, err := json.Marshal(example)
+			Expect(err).To(BeNil())
+			Expect(string(data)).NotTo(ContainSubstring("null"), "seed %d", seed)
+			var normalized interface{}
+			Expect(json.Unmarshal(data, &normalized)).To(Succeed())
+			Expect(v.ValidateAt(componentsPrefix+"Node", normalized)).To(BeEmpty(), "seed %d", seed)
+		}
+	})
+
+	It("should give numbers within bounds and multiples", func() {
+		for _, schema := range []string{
+			`{"type": "number", "minimum": 0, "maximum": 0.1, "exclusiveMinimum": true}`,
+			`{"type": "number", "minimum": 0, "maximum": 0.1, "exclusiveMinimum": true, "exclusiveMaximum": true}`,
+			`{"type": "number", "minimum": 1, "maximum": 1.2, "multipleOf": 0.1, "exclusiveMaximum": true}`,
+			`{"type": "number", "maximum": -3, "exclusiveMaximum": true, "multipleOf": 0.25}`,
+			`{"type": "number", "minimum": 0.3, "maximum": 0.4, "multipleOf": 0.5}`,
+			`{"type": "integer", "multipleOf": 2.5, "minimum": 1}`,
+			`{"type": "integer", "multipleOf": 0.75, "minimum": -10, "maximum": 10}`,
+			`{"type": "integer", "minimum": 1, "maximum": 2, "exclusiveMinimum": true}`,
+			`{"type": "integer", "maximum": 0, "exclusiveMaximum": true}`,
+			`{"type": "integer", "multipleOf": 0.001}`,
+			`{"multipleOf": 3, "minimum": 4, "maximum": 8}`,
+		} {
+			var s map[string]interface{}
+			Expect(json.Unmarshal([]byte(schema), &s)).To(Succeed())
+			v := validator.New(s, validator.OpenAPI30)
+			for seed := int64(0); seed < 50; seed++ {
+				example := GenerateExample(s, nil, seed)
+				if schema == `{"type": "number", "minimum": 0.3, "maximum": 0.4, "multipleOf": 0.5}` {
+					// no multiple fits, so middle of bounds is given
+					Expect(example).To(BeNumerically("~", 0.35))
+					continue
+				}
+				Expect(v.Validate(example)).To(BeEmpty(), "sample %v of %s with seed %d", example, schema, seed)
+			}
+		}
+	})
+
+	It("should give valid examples of every component, with conditions, dependencies and discriminators", func() {
+		definitions := syntheticSchema(20)["definitions"].(map[string]interface{})
+		for name, schema := range decodeDefinitions(fixtures.DiscriminatorJSON) {
+			definitions[name] = schema
+		}
+		definitions["v1events.Event"] = map[string]interface{}{"type": "object", "properties": map[string]interface{}{"v1": map[string]interface{}{"type": "string"}}}
+		definitions["v2events.Event"] = map[string]interface{}{"type": "object", "properties": map[string]interface{}{"v2": map[string]interface{}{"type": "integer"}}}
+		definitions["Dependent"] = map[string]interface{}{
+			"type":       "object",
+			"required":   []interface{}{"id"},
+			"properties": map[string]interface{}{"id": map[string]interface{}{"type": "integer"}, "a": map[string]interface{}{"type": "string"}},
+			"anyOf": []interface{}{
+				map[string]interface{}{"not": map[string]interface{}{"required": []interface{}{"a"}}},
+				map[string]interface{}{"required": []interface{}{"a"}},
+			},
+		}
+		for seed := int64(0); seed < 5; seed++ {
+			var diagnostics []string
+			res, err := TranslateDefinitionsWithOptions(definitions, Options{
+				GenerateExamples: true,
+				ExampleSeed:      seed,
+				OnDiagnostic: func(d Diagnostic) {
+					diagnostics = append(diagnostics, d.Message)
+				},
+			})
+			Expect(err).To(BeNil())
+			data, err := json.Marshal(res)
+			Expect(err).To(BeNil())
+			var components map[string]interface{}
+			Expect(json.Unmarshal(data, &components)).To(Succeed())
+
+			v := validator.New(map[string]interface{}{
+				"components": map[string]interface{}{"schemas": components},
+			}, validator.OpenAPI30)
+			for name, c := range components {
+				Expect(c).To(HaveKey("example"), "example of %s with seed %d", name, seed)
+				example := c.(map[string]interface{})["example"]
+				Expect(v.ValidateAt(componentsPrefix+name, example)).To(BeEmpty(), "example of %s with seed %d", name, seed)
+			}
+			Expect(diagnostics).NotTo(ContainElement("not able to generate valid example"))
+		}
+	})
+
+	It("should leave out example which is not valid", func() {
+		var diagnostics []string
+		// cases of discriminator reference components which do not exist
+		res, err := TranslateDefinitionsWithOptions(decodeDefinitions(fixtures.DiscriminatorJSON), Options{
+			GenerateExamples: true,
+			OnDiagnostic: func(d Diagnostic) {
+				diagnostics = append(diagnostics, d.String())
+			},
+		})
+		Expect(err).To(BeNil())
+		Expect(res["events.Event"]).NotTo(HaveKey("example"))
+		Expect(diagnostics).To(ContainElement("#/components/schemas/events.Event: not able to generate valid example"))
+	})
+
+	It("should use format samples only when they fit length bounds", func() {
+		for _, schema := range []map[string]interface{}{
+			{"type": "string", "format": "email", "minLength": 20.0},
+			{"type": "string", "format": "uuid", "maxLength": 8.0},
+		} {
+			for seed := int64(0); seed < 10; seed++ {
+				example := GenerateExample(schema, nil, seed)
+				Expect(validator.New(schema, validator.OpenAPI30).Validate(example)).To(BeEmpty())
+			}
+		}
+		Expect(GenerateExample(map[string]interface{}{"type": "string", "format": "email"}, nil, 0)).To(Equal("user@example.com"))
+	})
+
+	It("should not make required properties without schema null", func() {
+		example := GenerateExample(map[string]interface{}{
+			"type":     "object",
+			"required": []interface{}{"id"},
+		}, nil, 0)
+		Expect(example).To(HaveKeyWithValue("id", Not(BeNil())))
+	})
+})
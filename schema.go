@@ -0,0 +1,437 @@
+package jsonschema2openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// JSONSchema is JSON Schema draft-07 schema. Boolean schemas have only Boolean set.
+// Numbers are kept exact as json.Number.
+// Keywords not listed here are kept in Extensions, so decoding and encoding schema loses nothing
+// except keywords with default values, like "uniqueItems": false.
+type JSONSchema struct {
+	Boolean *bool `json:"-"`
+
+	Ref     string `json:"$ref,omitempty"`
+	Schema  string `json:"$schema,omitempty"`
+	ID      string `json:"$id,omitempty"`
+	Comment string `json:"$comment,omitempty"`
+
+	Title       string        `json:"title,omitempty"`
+	Description string        `json:"description,omitempty"`
+	Default     interface{}   `json:"default,omitempty"`
+	Examples    []interface{} `json:"examples,omitempty"`
+	ReadOnly    bool          `json:"readOnly,omitempty"`
+	WriteOnly   bool          `json:"writeOnly,omitempty"`
+
+	Type  Types         `json:"type,omitempty"`
+	Enum  []interface{} `json:"enum,omitempty"`
+	Const interface{}   `json:"const,omitempty"`
+
+	MultipleOf       json.Number `json:"multipleOf,omitempty"`
+	Maximum          json.Number `json:"maximum,omitempty"`
+	ExclusiveMaximum *Bound      `json:"exclusiveMaximum,omitempty"`
+	Minimum          json.Number `json:"minimum,omitempty"`
+	ExclusiveMinimum *Bound      `json:"exclusiveMinimum,omitempty"`
+
+	MaxLength        *int   `json:"maxLength,omitempty"`
+	MinLength        *int   `json:"minLength,omitempty"`
+	Pattern          string `json:"pattern,omitempty"`
+	Format           string `json:"format,omitempty"`
+	ContentMediaType string `json:"contentMediaType,omitempty"`
+	ContentEncoding  string `json:"contentEncoding,omitempty"`
+
+	// Items is schema of every item, and TupleItems are schemas of items by position. Only one of them is set.
+	Items           *JSONSchema   `json:"-"`
+	TupleItems      []*JSONSchema `json:"-"`
+	AdditionalItems *JSONSchema   `json:"additionalItems,omitempty"`
+	MaxItems        *int          `json:"maxItems,omitempty"`
+	MinItems        *int          `json:"minItems,omitempty"`
+	UniqueItems     bool          `json:"uniqueItems,omitempty"`
+	Contains        *JSONSchema   `json:"contains,omitempty"`
+
+	MaxProperties        *int                   `json:"maxProperties,omitempty"`
+	MinProperties        *int                   `json:"minProperties,omitempty"`
+	Required             []string               `json:"required,omitempty"`
+	Properties           map[string]*JSONSchema `json:"properties,omitempty"`
+	PatternProperties    map[string]*JSONSchema `json:"patternProperties,omitempty"`
+	AdditionalProperties *JSONSchema            `json:"additionalProperties,omitempty"`
+	Dependencies         map[string]*Dependency `json:"dependencies,omitempty"`
+	PropertyNames        *JSONSchema            `json:"propertyNames,omitempty"`
+
+	If   *JSONSchema `json:"if,omitempty"`
+	Then *JSONSchema `json:"then,omitempty"`
+	Else *JSONSchema `json:"else,omitempty"`
+
+	AllOf []*JSONSchema `json:"allOf,omitempty"`
+	AnyOf []*JSONSchema `json:"anyOf,omitempty"`
+	OneOf []*JSONSchema `json:"oneOf,omitempty"`
+	Not   *JSONSchema   `json:"not,omitempty"`
+
+	Definitions map[string]*JSONSchema `json:"definitions,omitempty"`
+
+	// Extensions are all other keywords
+	Extensions map[string]interface{} `json:"-"`
+}
+
+// Dependency is value of JSON Schema "dependencies": either schema or list of required properties
+type Dependency struct {
+	Schema   *JSONSchema
+	Required []string
+}
+
+// Schema is OpenAPI 3.0 Schema Object.
+// "x-" extensions and any other keywords not listed here are kept in Extensions.
+type Schema struct {
+	Ref string `json:"$ref,omitempty"`
+
+	Title        string        `json:"title,omitempty"`
+	Description  string        `json:"description,omitempty"`
+	Default      interface{}   `json:"default,omitempty"`
+	Example      interface{}   `json:"example,omitempty"`
+	ReadOnly     bool          `json:"readOnly,omitempty"`
+	WriteOnly    bool          `json:"writeOnly,omitempty"`
+	Deprecated   bool          `json:"deprecated,omitempty"`
+	ExternalDocs *ExternalDocs `json:"externalDocs,omitempty"`
+	XML          *XML          `json:"xml,omitempty"`
+
+	// Type has single type in OpenAPI 3.0, but translation keeps lists of types, like ["string", "integer"]
+	Type     Types         `json:"type,omitempty"`
+	Format   string        `json:"format,omitempty"`
+	Nullable bool          `json:"nullable,omitempty"`
+	Enum     []interface{} `json:"enum,omitempty"`
+
+	MultipleOf       json.Number `json:"multipleOf,omitempty"`
+	Maximum          json.Number `json:"maximum,omitempty"`
+	ExclusiveMaximum bool        `json:"exclusiveMaximum,omitempty"`
+	Minimum          json.Number `json:"minimum,omitempty"`
+	ExclusiveMinimum bool        `json:"exclusiveMinimum,omitempty"`
+
+	MaxLength *int   `json:"maxLength,omitempty"`
+	MinLength *int   `json:"minLength,omitempty"`
+	Pattern   string `json:"pattern,omitempty"`
+
+	Items       *Schema `json:"items,omitempty"`
+	MaxItems    *int    `json:"maxItems,omitempty"`
+	MinItems    *int    `json:"minItems,omitempty"`
+	UniqueItems bool    `json:"uniqueItems,omitempty"`
+
+	MaxProperties *int               `json:"maxProperties,omitempty"`
+	MinProperties *int               `json:"minProperties,omitempty"`
+	Required      []string           `json:"required,omitempty"`
+	Properties    map[string]*Schema `json:"properties,omitempty"`
+	// AdditionalPropertiesAllowed is set when additionalProperties is boolean, otherwise AdditionalProperties is
+	AdditionalPropertiesAllowed *bool   `json:"-"`
+	AdditionalProperties        *Schema `json:"-"`
+
+	AllOf         []*Schema      `json:"allOf,omitempty"`
+	AnyOf         []*Schema      `json:"anyOf,omitempty"`
+	OneOf         []*Schema      `json:"oneOf,omitempty"`
+	Not           *Schema        `json:"not,omitempty"`
+	Discriminator *Discriminator `json:"discriminator,omitempty"`
+
+	// Extensions are "x-" extensions and all other keywords
+	Extensions map[string]interface{} `json:"-"`
+}
+
+// Discriminator is OpenAPI 3.0 Discriminator Object
+type Discriminator struct {
+	PropertyName string            `json:"propertyName"`
+	Mapping      map[string]string `json:"mapping,omitempty"`
+}
+
+// XML is OpenAPI 3.0 XML Object
+type XML struct {
+	Name      string `json:"name,omitempty"`
+	Namespace string `json:"namespace,omitempty"`
+	Prefix    string `json:"prefix,omitempty"`
+	Attribute bool   `json:"attribute,omitempty"`
+	Wrapped   bool   `json:"wrapped,omitempty"`
+}
+
+// ExternalDocs is OpenAPI 3.0 External Documentation Object
+type ExternalDocs struct {
+	Description string `json:"description,omitempty"`
+	URL         string `json:"url"`
+}
+
+// Types is value of JSON Schema "type", which is either single type or list of them
+type Types []string
+
+// Bound is value of "exclusiveMaximum" or "exclusiveMinimum": number since draft-06, or boolean in draft-04,
+// which makes "maximum" or "minimum" exclusive. Translation accepts both forms.
+type Bound struct {
+	Number json.Number
+	// Exclusive is set for boolean form
+	Exclusive *bool
+}
+
+// Null is JSON null, for values like Default and Const which could be null, to tell them from missing ones
+type Null struct{}
+
+// MarshalJSON encodes null
+func (Null) MarshalJSON() ([]byte, error) {
+	return []byte("null"), nil
+}
+
+// TranslateSchemas is TranslateDefinitionsWithOptions for typed schemas
+func TranslateSchemas(definitions map[string]*JSONSchema, opts Options) (map[string]*Schema, error) {
+	var generic map[string]interface{}
+	if err := convertJSON(definitions, &generic); err != nil {
+		return nil, err
+	}
+	components, err := TranslateDefinitionsWithOptions(generic, opts)
+	if err != nil {
+		return nil, err
+	}
+	var res map[string]*Schema
+	if err := convertJSON(components, &res); err != nil {
+		return nil, fmt.Errorf("Error %s. Not able to decode translated schemas", err.Error())
+	}
+	return res, nil
+}
+
+// convertJSON converts value to other type with the same JSON representation
+func convertJSON(from, to interface{}) error {
+	data, err := json.Marshal(from)
+	if err != nil {
+		return err
+	}
+	return decodeJSON(data, to)
+}
+
+// Aliases of types, which are encoded without their MarshalJSON methods
+type (
+	plainJSONSchema JSONSchema
+	plainSchema     Schema
+)
+
+var (
+	jsonSchemaFields = jsonKeys(reflect.TypeOf(JSONSchema{}), "items")
+	schemaFields     = jsonKeys(reflect.TypeOf(Schema{}), "additionalProperties")
+)
+
+// MarshalJSON encodes schema with its extensions
+func (s JSONSchema) MarshalJSON() ([]byte, error) {
+	if s.Boolean != nil {
+		return json.Marshal(*s.Boolean)
+	}
+	data, err := json.Marshal(plainJSONSchema(s))
+	if err != nil {
+		return nil, err
+	}
+	keywords := make(map[string]interface{})
+	switch {
+	case s.TupleItems != nil:
+		keywords["items"] = s.TupleItems
+	case s.Items != nil:
+		keywords["items"] = s.Items
+	}
+	return withKeywords(data, keywords, s.Extensions)
+}
+
+// UnmarshalJSON decodes schema, putting unknown keywords into extensions
+func (s *JSONSchema) UnmarshalJSON(data []byte) error {
+	if b, ok := jsonBool(data); ok {
+		*s = JSONSchema{Boolean: &b}
+		return nil
+	}
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	var plain plainJSONSchema
+	if err := decodeJSON(data, &plain); err != nil {
+		return err
+	}
+	*s = JSONSchema(plain)
+	if items, ok := raw["items"]; ok {
+		var err error
+		if bytes.HasPrefix(bytes.TrimSpace(items), []byte("[")) {
+			err = json.Unmarshal(items, &s.TupleItems)
+		} else {
+			err = json.Unmarshal(items, &s.Items)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	s.Default = nullable(raw, "default", s.Default)
+	s.Const = nullable(raw, "const", s.Const)
+	var err error
+	s.Extensions, err = unknownKeywords(raw, jsonSchemaFields)
+	return err
+}
+
+// MarshalJSON encodes dependency
+func (d Dependency) MarshalJSON() ([]byte, error) {
+	if d.Schema != nil {
+		return json.Marshal(d.Schema)
+	}
+	if d.Required == nil {
+		return []byte("[]"), nil
+	}
+	return json.Marshal(d.Required)
+}
+
+// UnmarshalJSON decodes dependency
+func (d *Dependency) UnmarshalJSON(data []byte) error {
+	*d = Dependency{}
+	if bytes.HasPrefix(bytes.TrimSpace(data), []byte("[")) {
+		return json.Unmarshal(data, &d.Required)
+	}
+	return json.Unmarshal(data, &d.Schema)
+}
+
+// MarshalJSON encodes schema with its extensions
+func (s Schema) MarshalJSON() ([]byte, error) {
+	data, err := json.Marshal(plainSchema(s))
+	if err != nil {
+		return nil, err
+	}
+	keywords := make(map[string]interface{})
+	switch {
+	case s.AdditionalPropertiesAllowed != nil:
+		keywords["additionalProperties"] = *s.AdditionalPropertiesAllowed
+	case s.AdditionalProperties != nil:
+		keywords["additionalProperties"] = s.AdditionalProperties
+	}
+	return withKeywords(data, keywords, s.Extensions)
+}
+
+// UnmarshalJSON decodes schema, putting unknown keywords into extensions
+func (s *Schema) UnmarshalJSON(data []byte) error {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	var plain plainSchema
+	if err := decodeJSON(data, &plain); err != nil {
+		return err
+	}
+	*s = Schema(plain)
+	if additional, ok := raw["additionalProperties"]; ok {
+		if b, ok := jsonBool(additional); ok {
+			s.AdditionalPropertiesAllowed = &b
+		} else if err := json.Unmarshal(additional, &s.AdditionalProperties); err != nil {
+			return err
+		}
+	}
+	s.Default = nullable(raw, "default", s.Default)
+	s.Example = nullable(raw, "example", s.Example)
+	var err error
+	s.Extensions, err = unknownKeywords(raw, schemaFields)
+	return err
+}
+
+// MarshalJSON encodes single type as string, and several as array
+func (t Types) MarshalJSON() ([]byte, error) {
+	if len(t) == 1 {
+		return json.Marshal(t[0])
+	}
+	return json.Marshal([]string(t))
+}
+
+// UnmarshalJSON decodes either string or array of them
+func (t *Types) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*t = Types{single}
+		return nil
+	}
+	return json.Unmarshal(data, (*[]string)(t))
+}
+
+// MarshalJSON encodes either boolean or number
+func (b Bound) MarshalJSON() ([]byte, error) {
+	if b.Exclusive != nil {
+		return json.Marshal(*b.Exclusive)
+	}
+	return json.Marshal(b.Number)
+}
+
+// UnmarshalJSON decodes either boolean or number
+func (b *Bound) UnmarshalJSON(data []byte) error {
+	*b = Bound{}
+	if exclusive, ok := jsonBool(data); ok {
+		b.Exclusive = &exclusive
+		return nil
+	}
+	return decodeJSON(data, &b.Number)
+}
+
+// jsonKeys returns names of JSON fields of struct type, together with names of fields encoded by hand
+func jsonKeys(t reflect.Type, manual ...string) map[string]bool {
+	res := make(map[string]bool)
+	for i := 0; i < t.NumField(); i++ {
+		name := strings.Split(t.Field(i).Tag.Get("json"), ",")[0]
+		if name != "" && name != "-" {
+			res[name] = true
+		}
+	}
+	for _, name := range manual {
+		res[name] = true
+	}
+	return res
+}
+
+// withKeywords adds keywords and extensions to encoded object
+func withKeywords(data []byte, keywords, extensions map[string]interface{}) ([]byte, error) {
+	if len(keywords) == 0 && len(extensions) == 0 {
+		return data, nil
+	}
+	var obj map[string]json.RawMessage
+	if err := json.Unmarshal(data, &obj); err != nil {
+		return nil, err
+	}
+	for _, values := range []map[string]interface{}{extensions, keywords} {
+		for k, v := range values {
+			encoded, err := json.Marshal(v)
+			if err != nil {
+				return nil, err
+			}
+			obj[k] = encoded
+		}
+	}
+	return json.Marshal(obj)
+}
+
+// unknownKeywords decodes keywords of raw object which are not known
+func unknownKeywords(raw map[string]json.RawMessage, known map[string]bool) (map[string]interface{}, error) {
+	var res map[string]interface{}
+	for k, v := range raw {
+		if known[k] {
+			continue
+		}
+		if res == nil {
+			res = make(map[string]interface{})
+		}
+		var value interface{}
+		if err := decodeJSON(v, &value); err != nil {
+			return nil, err
+		}
+		res[k] = value
+	}
+	return res, nil
+}
+
+// nullable returns Null if keyword of raw object is null, otherwise decoded value
+func nullable(raw map[string]json.RawMessage, keyword string, value interface{}) interface{} {
+	if v, ok := raw[keyword]; ok && string(bytes.TrimSpace(v)) == "null" {
+		return Null{}
+	}
+	return value
+}
+
+func jsonBool(data []byte) (value bool, ok bool) {
+	switch string(bytes.TrimSpace(data)) {
+	case "true":
+		return true, true
+	case "false":
+		return false, true
+	}
+	return false, false
+}
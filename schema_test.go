@@ -0,0 +1,141 @@
+package jsonschema2openapi
+
+import (
+	"encoding/json"
+
+	"github.com/bunyk/jsonschema2openapi/fixtures"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+// expectLossless checks that JSON decoded into value and encoded back stays the same
+func expectLossless(data string, value interface{}) {
+	Expect(json.Unmarshal([]byte(data), value)).To(Succeed())
+	res, err := json.Marshal(value)
+	Expect(err).To(BeNil())
+	Expect(res).To(MatchJSON(data))
+}
+
+var _ = Describe("JSONSchema", func() {
+	It("should decode and encode discriminator fixture without loss", func() {
+		var schema JSONSchema
+		expectLossless(fixtures.DiscriminatorJSON, &schema)
+		Expect(schema.Definitions).To(HaveKey("events.Event"))
+		Expect(schema.Definitions["events.Event"].OneOf).To(HaveLen(2))
+	})
+
+	It("should keep boolean schemas, tuples, dependencies, nulls and unknown keywords", func() {
+		var schema JSONSchema
+		expectLossless(`{
+			"type": ["object", "null"],
+			"properties": {
+				"point": {"items": [{"type": "number"}, {"type": "number"}], "additionalItems": false},
+				"tags": {"items": {"type": "string"}, "default": null},
+				"any": true
+			},
+			"dependencies": {"a": ["b"], "c": {"required": ["d"]}},
+			"const": null,
+			"goType": "Thing",
+			"x-internal": {"nested": [1, 2]}
+		}`, &schema)
+		Expect(schema.Type).To(Equal(Types{"object", "null"}))
+		Expect(schema.Properties["point"].TupleItems).To(HaveLen(2))
+		Expect(*schema.Properties["point"].AdditionalItems.Boolean).To(BeFalse())
+		Expect(schema.Properties["tags"].Items.Type).To(Equal(Types{"string"}))
+		Expect(schema.Properties["tags"].Default).To(Equal(Null{}))
+		Expect(schema.Dependencies["a"].Required).To(Equal([]string{"b"}))
+		Expect(schema.Dependencies["c"].Schema.Required).To(Equal([]string{"d"}))
+		Expect(schema.Extensions).To(Equal(map[string]interface{}{
+			"goType":     "Thing",
+			"x-internal": map[string]interface{}{"nested": []interface{}{json.Number("1"), json.Number("2")}},
+		}))
+	})
+
+	It("should keep numeric and draft-04 boolean exclusive bounds", func() {
+		var schema JSONSchema
+		expectLossless(`{
+			"properties": {
+				"draft04": {"minimum": 0, "exclusiveMinimum": true, "maximum": 10, "exclusiveMaximum": false},
+				"draft07": {"exclusiveMinimum": 0, "exclusiveMaximum": 10.5}
+			}
+		}`, &schema)
+		draft04 := schema.Properties["draft04"]
+		Expect(*draft04.ExclusiveMinimum.Exclusive).To(BeTrue())
+		Expect(*draft04.ExclusiveMaximum.Exclusive).To(BeFalse())
+		draft07 := schema.Properties["draft07"]
+		Expect(draft07.ExclusiveMinimum).To(Equal(&Bound{Number: "0"}))
+		Expect(draft07.ExclusiveMaximum).To(Equal(&Bound{Number: "10.5"}))
+	})
+})
+
+var _ = Describe("Schema", func() {
+	It("should keep boolean additionalProperties and extensions", func() {
+		var schema Schema
+		expectLossless(`{
+			"type": "object",
+			"additionalProperties": false,
+			"properties": {
+				"map": {"additionalProperties": {"type": "integer"}},
+				"value": {"example": null, "x-patternProperties": {"^a": {}}}
+			},
+			"discriminator": {"propertyName": "kind", "mapping": {"a": "#/components/schemas/A"}}
+		}`, &schema)
+		Expect(*schema.AdditionalPropertiesAllowed).To(BeFalse())
+		Expect(schema.Properties["map"].AdditionalProperties.Type).To(Equal(Types{"integer"}))
+		Expect(schema.Properties["value"].Example).To(Equal(Null{}))
+		Expect(schema.Properties["value"].Extensions).To(HaveKey("x-patternProperties"))
+		Expect(schema.Discriminator.Mapping["a"]).To(Equal("#/components/schemas/A"))
+	})
+
+	It("should keep lists of types", func() {
+		var schema Schema
+		expectLossless(`{"type": ["string", "integer"], "properties": {"a": {"type": "string"}}}`, &schema)
+		Expect(schema.Type).To(Equal(Types{"string", "integer"}))
+		Expect(schema.Properties["a"].Type).To(Equal(Types{"string"}))
+	})
+})
+
+var _ = Describe("TranslateSchemas", func() {
+	It("should translate schemas built in code", func() {
+		components, err := TranslateSchemas(map[string]*JSONSchema{
+			"Pet": {
+				Type:     Types{"object"},
+				Required: []string{"name"},
+				Properties: map[string]*JSONSchema{
+					"name":  {Type: Types{"string"}, Examples: []interface{}{"Rex"}},
+					"age":   {Type: Types{"integer"}, ExclusiveMinimum: &Bound{Number: "0"}},
+					"owner": {OneOf: []*JSONSchema{{Ref: "#/definitions/Owner"}, {Type: Types{"null"}}}},
+				},
+			},
+			"Owner": {Type: Types{"string"}},
+		}, Options{})
+		Expect(err).To(BeNil())
+
+		pet := components["Pet"]
+		Expect(pet.Properties["name"].Example).To(Equal("Rex"))
+		Expect(pet.Properties["age"].Minimum).To(Equal(json.Number("0")))
+		Expect(pet.Properties["age"].ExclusiveMinimum).To(BeTrue())
+		Expect(pet.Properties["owner"].OneOf[0].Ref).To(Equal("#/components/schemas/Owner"))
+	})
+
+	It("should decode translated lists of types and draft-04 bounds", func() {
+		exclusive := true
+		components, err := TranslateSchemas(map[string]*JSONSchema{
+			"Value": {Type: Types{"string", "integer"}, Minimum: "0", ExclusiveMinimum: &Bound{Exclusive: &exclusive}},
+		}, Options{})
+		Expect(err).To(BeNil())
+
+		value := components["Value"]
+		Expect(value.Type).To(Equal(Types{"string", "integer"}))
+		Expect(value.Minimum).To(Equal(json.Number("0")))
+		Expect(value.ExclusiveMinimum).To(BeTrue())
+	})
+
+	It("should return translation errors", func() {
+		_, err := TranslateSchemas(map[string]*JSONSchema{
+			"Pet": {Extensions: map[string]interface{}{"goType": "Pet"}},
+		}, Options{UnknownKeywords: RejectUnknown})
+		Expect(err).NotTo(BeNil())
+	})
+})
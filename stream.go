@@ -0,0 +1,127 @@
+package jsonschema2openapi
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// Convert is PutSchemaIntoOpenAPIWithOptions which reads JSON schema and OpenAPI template from readers,
+// and writes resulting spec to w, followed by newline. Inputs are decoded while they are read, and spec is encoded
+// while it is written, so neither input nor output text is held in memory as a whole. Reading, translation and
+// writing stop when ctx is done, returning ctx.Err().
+//
+// Inputs are read only up to opts.MaxInputSize, and nesting is checked while reading, so it is safe to pass
+// request bodies of untrusted clients.
+func Convert(ctx context.Context, schema, template io.Reader, w io.Writer, opts Options) error {
+	spec, order, err := putSchema(ctx, schema, template, opts)
+	if err != nil {
+		return err
+	}
+
+	out := bufio.NewWriter(contextWriter{ctx: ctx, w: w})
+	if err := newOrderedEncoder(out, " ").encode(spec, order, 0); err != nil {
+		return fmt.Errorf("Error %s. Not able to output OpenAPI spec", err.Error())
+	}
+	out.WriteByte('\n')
+	return out.Flush()
+}
+
+// LimitError is returned when input exceeds Options.MaxInputSize or Options.MaxDepth
+type LimitError struct {
+	// Input is "JSON schema" or "OpenAPI template", or "OpenAPI spec or JSON schema" for ReadComponents
+	Input   string
+	Message string
+}
+
+func (e *LimitError) Error() string {
+	return e.Input + " " + e.Message
+}
+
+var (
+	errInputTooLarge = errors.New("input is too large")
+	errInputTooDeep  = errors.New("input is nested too deep")
+)
+
+// decodeInput decodes JSON object from r, together with order of its keys, in one pass. Input is read only
+// up to one byte over opts.MaxInputSize, and decoding stops on objects and arrays nested deeper
+// than opts.MaxDepth. Those errors are returned as LimitError.
+func decodeInput(ctx context.Context, r io.Reader, input string, opts Options) (map[string]interface{}, *keyOrder, error) {
+	r = contextReader{ctx: ctx, r: r}
+	if opts.MaxInputSize > 0 {
+		r = &sizeLimitReader{r: r, left: opts.MaxInputSize + 1}
+	}
+	value, order, err := decodeOrdered(r, opts.MaxDepth)
+	switch err {
+	case nil:
+	case errInputTooLarge:
+		return nil, nil, &LimitError{Input: input, Message: fmt.Sprintf("is larger than %d bytes", opts.MaxInputSize)}
+	case errInputTooDeep:
+		return nil, nil, &LimitError{Input: input, Message: fmt.Sprintf("is nested deeper than %d levels", opts.MaxDepth)}
+	default:
+		if ctx.Err() != nil {
+			return nil, nil, ctx.Err()
+		}
+		return nil, nil, err
+	}
+	obj, ok := value.(map[string]interface{})
+	if !ok {
+		return nil, nil, errors.New("value is not an object")
+	}
+	return obj, order, nil
+}
+
+// inputError returns error of decodeInput as it is when it is LimitError or ctx is done,
+// otherwise it is formatted with format
+func inputError(ctx context.Context, err error, format string) error {
+	if _, ok := err.(*LimitError); ok || ctx.Err() != nil {
+		return err
+	}
+	return fmt.Errorf(format, err.Error())
+}
+
+// sizeLimitReader fails with errInputTooLarge when more than left bytes are read
+type sizeLimitReader struct {
+	r    io.Reader
+	left int64
+}
+
+func (l *sizeLimitReader) Read(p []byte) (int, error) {
+	if int64(len(p)) > l.left {
+		p = p[:l.left]
+	}
+	n, err := l.r.Read(p)
+	l.left -= int64(n)
+	if l.left <= 0 {
+		return n, errInputTooLarge
+	}
+	return n, err
+}
+
+// contextReader fails reads after ctx is done
+type contextReader struct {
+	ctx context.Context
+	r   io.Reader
+}
+
+func (c contextReader) Read(p []byte) (int, error) {
+	if err := c.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return c.r.Read(p)
+}
+
+// contextWriter fails writes after ctx is done
+type contextWriter struct {
+	ctx context.Context
+	w   io.Writer
+}
+
+func (c contextWriter) Write(p []byte) (int, error) {
+	if err := c.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return c.w.Write(p)
+}
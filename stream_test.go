@@ -0,0 +1,114 @@
+package jsonschema2openapi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/bunyk/jsonschema2openapi/fixtures"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+// cancellingWriter cancels context on first write
+type cancellingWriter struct {
+	cancel func()
+	buf    bytes.Buffer
+}
+
+func (w *cancellingWriter) Write(p []byte) (int, error) {
+	w.cancel()
+	return w.buf.Write(p)
+}
+
+var _ = Describe("Convert", func() {
+	It("should write the same spec as PutSchemaIntoOpenAPI", func() {
+		opts := Options{IncludeRoot: true, RootName: "Root", NamedExamples: true}
+		expected, err := PutSchemaIntoOpenAPIWithOptions(fixtures.DiscriminatorJSON, orderTemplate, opts)
+		Expect(err).To(BeNil())
+
+		var out bytes.Buffer
+		err = Convert(context.Background(), strings.NewReader(fixtures.DiscriminatorJSON), strings.NewReader(orderTemplate), &out, opts)
+		Expect(err).To(BeNil())
+		Expect(out.String()).To(Equal(expected + "\n"))
+	})
+
+	It("should not read input over size limit", func() {
+		schema := strings.NewReader(fixtures.DiscriminatorJSON)
+		var out bytes.Buffer
+		err := Convert(context.Background(), schema, strings.NewReader(minOpenAPI), &out, Options{MaxInputSize: 100})
+		Expect(err).To(BeAssignableToTypeOf(&LimitError{}))
+		Expect(err.Error()).To(Equal("JSON schema is larger than 100 bytes"))
+		Expect(schema.Len()).To(Equal(len(fixtures.DiscriminatorJSON) - 101))
+		Expect(out.Len()).To(Equal(0))
+
+		limit := int64(len(minOpenAPI))
+		err = Convert(context.Background(), strings.NewReader(`{}`), strings.NewReader(minOpenAPI), &out, Options{MaxInputSize: limit})
+		Expect(err).To(BeNil())
+		err = Convert(context.Background(), strings.NewReader(`{}`), strings.NewReader(minOpenAPI+" "), &out, Options{MaxInputSize: limit})
+		Expect(err).To(Equal(&LimitError{Input: "OpenAPI template", Message: fmt.Sprintf("is larger than %d bytes", limit)}))
+	})
+
+	It("should reject too deeply nested input", func() {
+		template := `{"openapi": "3.0.0", "components": {"schemas": {}}, "x-deep": [[[[{"a": "[[[["}]]]]}`
+		opts := Options{MaxDepth: 6}
+		err := Convert(context.Background(), strings.NewReader(`{"definitions": {}}`), strings.NewReader(template), &bytes.Buffer{}, opts)
+		Expect(err).To(BeNil())
+
+		opts.MaxDepth = 5
+		err = Convert(context.Background(), strings.NewReader(`{"definitions": {}}`), strings.NewReader(template), &bytes.Buffer{}, opts)
+		Expect(err).To(Equal(&LimitError{Input: "OpenAPI template", Message: "is nested deeper than 5 levels"}))
+
+		_, err = PutSchemaIntoOpenAPIWithOptions(`{"definitions": {"A": {"items": {"items": {}}}}}`, minOpenAPI, Options{MaxDepth: 3})
+		Expect(err).To(Equal(&LimitError{Input: "JSON schema", Message: "is nested deeper than 3 levels"}))
+	})
+
+	It("should stop when context is cancelled", func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		err := Convert(ctx, strings.NewReader(fixtures.DiscriminatorJSON), strings.NewReader(minOpenAPI), &bytes.Buffer{}, Options{})
+		Expect(err).To(Equal(context.Canceled))
+	})
+
+	It("should stop writing when context is cancelled", func() {
+		schema, err := json.Marshal(syntheticSchema(100))
+		Expect(err).To(BeNil())
+		ctx, cancel := context.WithCancel(context.Background())
+		w := &cancellingWriter{cancel: cancel}
+		err = Convert(ctx, bytes.NewReader(schema), strings.NewReader(minOpenAPI), w, Options{})
+		Expect(err).To(Equal(context.Canceled))
+		Expect(w.buf.Len()).To(BeNumerically("<", len(schema)))
+	})
+})
+
+var _ = Describe("decodeOrdered", func() {
+	It("should count nesting of objects and arrays, but not brackets in strings", func() {
+		_, _, err := decodeOrdered(strings.NewReader(`{"a": [1, {"b": "}}]]\"{{"}]}`), 3)
+		Expect(err).To(BeNil())
+		_, _, err = decodeOrdered(strings.NewReader(`{"a": [1, {"b": "}}]]\"{{"}]}`), 2)
+		Expect(err).To(Equal(errInputTooDeep))
+		_, _, err = decodeOrdered(strings.NewReader(`"[[["`), 1)
+		Expect(err).To(BeNil())
+	})
+
+	It("should decode value and order of keys in one pass", func() {
+		value, order, err := decodeOrdered(strings.NewReader(`{"b": [1.50, {"d": null, "c": true}], "a": "x"}`), 0)
+		Expect(err).To(BeNil())
+		Expect(value).To(Equal(map[string]interface{}{
+			"b": []interface{}{json.Number("1.50"), map[string]interface{}{"d": nil, "c": true}},
+			"a": "x",
+		}))
+		Expect(order.keys).To(Equal([]string{"b", "a"}))
+		Expect(order.at("b", "1").keys).To(Equal([]string{"d", "c"}))
+	})
+
+	It("should reject data after value", func() {
+		_, _, err := decodeOrdered(strings.NewReader(`{} {}`), 0)
+		Expect(err).To(MatchError("invalid data after top-level value"))
+		_, _, err = decodeOrdered(strings.NewReader(`{}}`), 0)
+		Expect(err).NotTo(BeNil())
+	})
+})
@@ -1,84 +1,384 @@
 package jsonschema2openapi
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
+	"io"
 	"reflect"
+	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/jmoiron/jsonq"
 )
 
+// Options configures translation done by PutSchemaIntoOpenAPIWithOptions and TranslateDefinitionsWithOptions.
+// Zero value gives the same result as PutSchemaIntoOpenAPI and TranslateDefinitions.
+type Options struct {
+	// NameReplacement is put instead of every character which is not allowed in OpenAPI component name.
+	// Defaults to "_"
+	NameReplacement string
+	// NameFunc, if set, is applied to every definition name before invalid characters are replaced.
+	NameFunc func(name string) string
+
+	// IncludeRoot adds root schema itself to components/schemas, and references to "#" will point to it.
+	// Component is named by RootName, or if it is empty, by title or $id of the schema.
+	IncludeRoot bool
+	RootName    string
+
+	// KeepMetadata disables cleanup of JSON Schema only keywords. By default "$schema" and "$id" are removed,
+	// "$comment" is replaced with "x-comment" and nested "definitions" are moved to components/schemas.
+	KeepMetadata bool
+
+	// NamedExamples puts "examples" of components into components/examples of template as Example Objects
+	// named like COMPONENT_1, COMPONENT_2 and so on. Names already used by template get suffix like COMPONENT_1_2.
+	// Otherwise first of examples becomes "example",
+	// and the rest are kept in "x-examples". Examples of nested schemas are always converted the latter way.
+	// Has effect only for PutSchemaIntoOpenAPIWithOptions and Convert.
+	NamedExamples bool
+
+	// Formats maps values of JSON Schema "format" to OpenAPI ones. Format mapped to empty string is removed.
+	Formats map[string]string
+	// FormatFunc, if set, is applied to every format after Formats. Returned empty string removes format.
+	FormatFunc func(format string) string
+
+	// Keywords maps custom keywords to ones allowed by OpenAPI, like "goType" to "x-go-type".
+	// Keyword mapped to empty string is removed.
+	Keywords map[string]string
+	// UnknownKeywords tells what to do with keywords which are neither OpenAPI ones nor extensions
+	UnknownKeywords KeywordPolicy
+
+	// Validate makes PutSchemaIntoOpenAPIWithOptions check the result against OpenAPI 3.0 schema,
+	// and return ValidationErrors when it is not valid
+	Validate bool
+
+	// CheckRefs makes PutSchemaIntoOpenAPIWithOptions return DanglingRefsError when references of result
+	// point at nothing. PruneUnreachable removes components which are not reachable from the rest of template,
+	// like paths. See AnalyzeRefs.
+	CheckRefs        bool
+	PruneUnreachable bool
+
+	// GenerateExamples sets "example" of every component which has none, to instance generated from its schema.
+	// ExampleSeed makes generated examples different, the same seed always gives the same examples.
+	GenerateExamples bool
+	ExampleSeed      int64
+
+	// NullableAsTypeArray makes reverse translation turn nullable types into "type": [X, "null"],
+	// instead of "oneOf": [{"type": X}, {"type": "null"}]
+	NullableAsTypeArray bool
+
+	// OnDiagnostic, if set, is called for every place where translation is not exact
+	OnDiagnostic func(Diagnostic)
+
+	// MaxInputSize limits size in bytes of schema and of template, MaxDepth limits nesting of objects and arrays
+	// in them. Inputs over limits are rejected with LimitError. Zero means no limit.
+	MaxInputSize int64
+	MaxDepth     int
+
+	// Deduplicate merges every group of structurally equal components into one, and points references
+	// to it. With DeduplicateIgnoreAnnotations components which differ only in title, description,
+	// examples or comments are equal too. DeduplicateName chooses which of sorted names of equal components
+	// is kept, by default it is the first one.
+	Deduplicate                  bool
+	DeduplicateIgnoreAnnotations bool
+	DeduplicateName              func(names []string) string
+
+	// Workers, if more than 1, is number of goroutines which translate definitions at once.
+	// Result, errors and order of diagnostics are the same as of sequential translation, and OnDiagnostic
+	// is still called from one goroutine, but FormatFunc is called concurrently.
+	Workers int
+}
+
+// Diagnostic describes loss of precision during translation
+type Diagnostic struct {
+	// Pointer is reference to translated schema, like "#/components/schemas/User/properties/name"
+	Pointer string
+	Message string
+}
+
+func (d Diagnostic) String() string {
+	return d.Pointer + ": " + d.Message
+}
+
 // PutSchemaIntoOpenAPI returns OpenAPI spec based on template and JSONSchema which is added to its components/schemas
 func PutSchemaIntoOpenAPI(schemaJSON, openAPITemplate string) (string, error) {
-	var schema map[string]interface{}
-	err := json.Unmarshal([]byte(schemaJSON), &schema)
+	return PutSchemaIntoOpenAPIWithOptions(schemaJSON, openAPITemplate, Options{})
+}
+
+// PutSchemaIntoOpenAPIWithOptions is PutSchemaIntoOpenAPI with translation configured by opts
+func PutSchemaIntoOpenAPIWithOptions(schemaJSON, openAPITemplate string, opts Options) (string, error) {
+	spec, order, err := putSchema(context.Background(), strings.NewReader(schemaJSON), strings.NewReader(openAPITemplate), opts)
+	if err != nil {
+		return "", err
+	}
+	res, err := marshalOrdered(spec, order, " ")
+	if err != nil {
+		return "", fmt.Errorf("Error %s. Not able to output OpenAPI spec", err.Error())
+	}
+	return string(res), nil
+}
+
+// putSchema returns OpenAPI spec with translated schema, and order of keys in which it should be written
+func putSchema(ctx context.Context, schemaJSON, openAPITemplate io.Reader, opts Options) (map[string]interface{}, *keyOrder, error) {
+	schema, schemaOrder, err := decodeInput(ctx, schemaJSON, "JSON schema", opts)
 	if err != nil {
-		return "", fmt.Errorf("Error %s. Was not able to parse JSON schema", err.Error())
+		return nil, nil, inputError(ctx, err, "Error %s. Was not able to parse JSON schema")
 	}
 
 	// Load OpenAPI spec from string constant
-	var tmpl map[string]interface{}
-	err = json.Unmarshal([]byte(openAPITemplate), &tmpl)
+	tmpl, tmplOrder, err := decodeInput(ctx, openAPITemplate, "OpenAPI template", opts)
 	if err != nil {
-		return "", fmt.Errorf("Error %s. Not able to parse OpenAPI template", err.Error())
+		return nil, nil, inputError(ctx, err, "Error %s. Not able to parse OpenAPI template")
 	}
 
 	// Get componets.schemas object to fill up
 	jq := jsonq.NewQuery(tmpl)
 	schemas, err := jq.Object("components", "schemas")
 	if err != nil {
-		return "", fmt.Errorf("Error %s. Bad JSON template, no component.schemas object", err.Error())
+		return nil, nil, fmt.Errorf("Error %s. Bad JSON template, no component.schemas object", err.Error())
 	}
 
 	// Now add definitions from our schema to that OpenAPI
-	schema4OpenAPI := schema["definitions"].(map[string]interface{})
-	for k, v := range TranslateDefinitions(schema4OpenAPI) {
+	definitions, err := collectDefinitions(schema, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+	t, err := translate(ctx, definitions, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+	for k, v := range t.schemas {
 		schemas[k] = v
 	}
+	if len(t.examples) > 0 {
+		components := tmpl["components"].(map[string]interface{})
+		examples, ok := components["examples"].(map[string]interface{})
+		if !ok {
+			examples = make(map[string]interface{})
+			components["examples"] = examples
+		}
+		t.putExamples(examples)
+	}
 
-	// And output what we got
-	res, _ := json.MarshalIndent(tmpl, "", " ")
-	return string(res), nil
+	// Template could reference renamed or merged definitions too
+	tmpl = renameRefs(tmpl, componentsPrefix, t.renames).(map[string]interface{})
+	tmpl = mergeRefs(tmpl, false, t.merged).(map[string]interface{})
+
+	if opts.CheckRefs || opts.PruneUnreachable {
+		graph := AnalyzeRefs(tmpl)
+		if err := graph.Err(); opts.CheckRefs && err != nil {
+			return nil, nil, err
+		}
+		if opts.PruneUnreachable {
+			tmpl = graph.prune(tmpl)
+		}
+	}
+
+	if opts.Validate {
+		if err := validateSpec(tmpl); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	// Keys are written in order they were in template and schema
+	if components := tmplOrder.at("components", "schemas"); components != nil {
+		root := ""
+		if opts.IncludeRoot {
+			root = rootName(schema, opts)
+		}
+		t.componentsOrder(components, schemaOrder, root)
+	}
+	return tmpl, tmplOrder, ctx.Err()
 }
 
-// TranslateDefinitions translates JSON Schema definitons object to components/schemas of OpenAPI
+// TranslateDefinitions translates JSON Schema definitons object to components/schemas of OpenAPI.
+// definitions are not changed, so the same definitions could be translated by several goroutines at once.
 func TranslateDefinitions(definitions map[string]interface{}) map[string]interface{} {
-	schema4OpenAPI := replaceRefs(
-		definitions,
-		"#/definitions/", "#/components/schemas/",
-	).(map[string]interface{})
-	schema4OpenAPI = replaceNullable(schema4OpenAPI).(map[string]interface{})
-	schema4OpenAPI = discriminate(schema4OpenAPI).(map[string]interface{})
-	return materialImplication(schema4OpenAPI).(map[string]interface{})
+	res, _ := TranslateDefinitionsWithOptions(definitions, Options{})
+	return res
 }
 
-// Recursively replace old substring to new in any value of $ref key in json
-func replaceRefs(jsonData interface{}, old, new string) interface{} {
-	switch jsonData.(type) {
+// TranslateDefinitionsWithOptions is TranslateDefinitions with translation configured by opts
+func TranslateDefinitionsWithOptions(definitions map[string]interface{}, opts Options) (map[string]interface{}, error) {
+	// there is no components/examples to put named examples into, so they are kept in schemas
+	opts.NamedExamples = false
+	t, err := translate(context.Background(), definitions, opts)
+	if err != nil {
+		return nil, err
+	}
+	return t.schemas, nil
+}
+
+const (
+	definitionsPrefix = "#/definitions/"
+	componentsPrefix  = "#/components/schemas/"
+	componentsRoot    = "#/components/schemas"
+)
+
+// translation holds result of translating definitions
+type translation struct {
+	opts Options
+	// schemas are to be put into components/schemas
+	schemas map[string]interface{}
+	// renames maps original definition names to names of components which were changed
+	renames map[string]string
+	// moved maps pointers of nested definitions, relative to definitions, to names of components they were moved to
+	moved map[string]string
+	// merged maps names of components removed by deduplication to names of equal components which are kept
+	merged map[string]string
+	// examples are to be put into components/examples
+	examples map[string]interface{}
+	// errors found by passes, translation fails if there are any
+	errors []string
+}
+
+func translate(ctx context.Context, definitions map[string]interface{}, opts Options) (*translation, error) {
+	t := &translation{opts: opts}
+	if !opts.KeepMetadata && hasNestedDefinitions(definitions) {
+		definitions, t.moved = hoistDefinitions(definitions, definitionsPrefix)
+	}
+	definitions = booleanSchemaMap(definitions)
+	t.renames = sanitizeNames(definitions, opts)
+	if len(t.renames) > 0 {
+		renamed := make(map[string]interface{}, len(definitions))
+		for name, schema := range definitions {
+			renamed[t.name(name)] = schema
+		}
+		definitions = renamed
+	}
+	if opts.NamedExamples {
+		definitions, t.examples = namedExamples(definitions)
+	}
+	// definitions are translated in order of names, so diagnostics are reported in the same order every time
+	names := make([]string, 0, len(definitions))
+	for name := range definitions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	t.schemas = make(map[string]interface{}, len(definitions))
+	if opts.Workers > 1 {
+		if err := t.visitParallel(ctx, names, definitions); err != nil {
+			return nil, err
+		}
+	} else {
+		for _, name := range names {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+			t.schemas[name] = t.visit(componentsPrefix+escapeRefToken(name), definitions[name])
+		}
+	}
+	if opts.Deduplicate {
+		var err error
+		t.schemas, t.merged, err = t.deduplicate(t.schemas)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if opts.GenerateExamples {
+		t.schemas = t.generateExamples(t.schemas)
+	}
+	if len(t.errors) > 0 {
+		sort.Strings(t.errors)
+		return nil, fmt.Errorf("Error %s. Not able to translate definitions", strings.Join(t.errors, "; "))
+	}
+	return t, nil
+}
+
+// visit returns translated copy of jsonData, which is schema at pointer. Translation is done in single traversal:
+// discriminated cases are found before subschemas are visited, and other passes are applied to every schema
+// after its subschemas, in order they are listed in schema method. Values of data keywords and extensions
+// are not copied.
+func (t *translation) visit(pointer string, jsonData interface{}) interface{} {
+	switch v := jsonData.(type) {
 	case map[string]interface{}:
-		res := make(map[string]interface{})
-		for k, v := range jsonData.(map[string]interface{}) {
-			if k == "$ref" { // if schema has $ref
-				return map[string]interface{}{ // we do not need any other fields there
-					"$ref": strings.Replace(v.(string), old, new, 1),
+		if ref, ok := v["$ref"].(string); ok {
+			return map[string]interface{}{ // we do not need any other fields there
+				"$ref": t.ref(ref),
+			}
+		}
+		v = t.discriminate(v)
+		res := make(map[string]interface{}, len(v))
+		for k, value := range v {
+			schemas, ok := value.(map[string]interface{})
+			switch {
+			case !hasSubschemas(k):
+				// values of data keywords, extensions, and custom and unknown keywords are not schemas
+				res[k] = value
+			case schemaMapKeywords[k] && ok:
+				p := pointer + "/" + escapeRefToken(k)
+				copied := make(map[string]interface{}, len(schemas))
+				for name, schema := range schemas {
+					if isPropertyList(schema) {
+						copied[name] = schema
+						continue
+					}
+					copied[name] = t.visit(p+"/"+escapeRefToken(name), schema)
 				}
+				res[k] = copied
+			default:
+				res[k] = t.visit(pointer+"/"+escapeRefToken(k), value)
 			}
-			res[k] = replaceRefs(v, old, new)
 		}
-		return res
+		return t.schema(pointer, res)
 	case []interface{}:
-		res := make([]interface{}, 0)
-		for _, v := range jsonData.([]interface{}) {
-			res = append(res, replaceRefs(v, old, new))
+		res := make([]interface{}, len(v))
+		for i, elem := range v {
+			res[i] = t.visit(pointer+"/"+strconv.Itoa(i), elem)
 		}
 		return res
 	default:
+		return v
 	}
-	return jsonData
 }
 
-// discriminate replaces any occurences of
+// schema translates schema at pointer, which subschemas are already translated
+func (t *translation) schema(pointer string, schema map[string]interface{}) map[string]interface{} {
+	schema = replaceBooleanSchemas(schema)
+	if !t.opts.KeepMetadata {
+		schema = cleanupMetadata(schema)
+	}
+	schema = booleanExclusiveBounds(schema)
+	schema = convertExamples(schema)
+	schema = translateDependencies(schema)
+	schema = t.patternProperties(pointer, schema)
+	schema = t.tuples(pointer, schema)
+	return t.finish(pointer, schema)
+}
+
+// finish applies passes which follow tuples to schema at pointer. Tuples apply them to schemas they make.
+func (t *translation) finish(pointer string, schema map[string]interface{}) map[string]interface{} {
+	schema = t.formats(schema)
+	schema = replaceNullable(schema)
+	schema = materialImplication(schema)
+	return t.keywords(pointer, schema)
+}
+
+// ref returns reference to definition, pointed to its component
+func (t *translation) ref(ref string) string {
+	ref = strings.Replace(ref, definitionsPrefix, componentsPrefix, 1)
+	return renameRef(ref, componentsPrefix, t.renames)
+}
+
+// name returns name of component for definition
+func (t *translation) name(definition string) string {
+	if name, ok := t.renames[definition]; ok {
+		return name
+	}
+	return definition
+}
+
+// report passes diagnostic about schema at pointer to the user
+func (t *translation) report(pointer, format string, args ...interface{}) {
+	if t.opts.OnDiagnostic != nil {
+		t.opts.OnDiagnostic(Diagnostic{
+			Pointer: pointer,
+			Message: fmt.Sprintf(format, args...),
+		})
+	}
+}
+
+// discriminate replaces
 //
 //	"oneOf": [
 //		{
@@ -96,6 +396,7 @@ func replaceRefs(jsonData interface{}, old, new string) interface{} {
 // with
 //
 // "oneOf": [
+//
 //		{ "$ref": "REF1" },
 //		{ "$ref": "REF2" },
 //	],
@@ -107,87 +408,90 @@ func replaceRefs(jsonData interface{}, old, new string) interface{} {
 //		}
 //	}
 //
-func discriminate(jsonData interface{}) interface{} {
-	switch v := jsonData.(type) {
-	case map[string]interface{}:
-		ok, cases := getCases(v)
-		if ok {
-			v["oneOf"] = reflist(cases.Refs)
-			discriminator := make(map[string]interface{})
-			discriminator["propertyName"] = cases.Property
-			discriminator["mapping"] = cases2refmapping(cases.Cases, cases.Refs)
-			v["discriminator"] = discriminator
-		} else { // Go deeper
-			for k, subschema := range v {
-				v[k] = discriminate(subschema)
-			}
-		}
-		return v
-	case []interface{}:
-		res := make([]interface{}, 0)
-		for _, elem := range v {
-			res = append(res, discriminate(elem))
-		}
-		return res
-	default:
-		return v
+// Schema is returned as it is when it has no such oneOf, otherwise its copy is returned.
+// References in mapping are pointed to components, the ones in oneOf are left for visit.
+func (t *translation) discriminate(schema map[string]interface{}) map[string]interface{} {
+	ok, cases := getCases(schema)
+	if !ok {
+		return schema
+	}
+	res := make(map[string]interface{}, len(schema)+1)
+	for k, v := range schema {
+		res[k] = v
+	}
+	refs := make([]string, len(cases.Refs))
+	for i, ref := range cases.Refs {
+		refs[i] = t.ref(ref)
 	}
+	res["oneOf"] = reflist(cases.Refs)
+	res["discriminator"] = map[string]interface{}{
+		"propertyName": cases.Property,
+		"mapping":      cases2refmapping(cases.Cases, refs),
+	}
+	return res
 }
 
 // https://en.wikipedia.org/wiki/Material_implication_(rule_of_inference)
 // Turn
-// {
-// 	"if": CONDITION
-// 	"then": SCHEMA1
-// 	"else": SCHEMA2
-// }
 //
-// To
+//	{
+//		"if": CONDITION
+//		"then": SCHEMA1
+//		"else": SCHEMA2
+//	}
 //
-// {
-//   "anyOf": [
-//     { "allOf": [ CONDITION, SCHEMA1 ] },
-//     { "allOf": [ {"not": CONDITION }, SCHEMA2 ] }
-//   ]
-// }
-func materialImplication(jsonData interface{}) interface{} {
-	switch v := jsonData.(type) {
-	case map[string]interface{}:
-		ok, ifschema, thenschema, elseschema := getCondition(v)
-		if ok {
-			v["anyOf"] = []interface{}{
-				map[string][]interface{}{
-					"allOf": []interface{}{
-						ifschema, thenschema,
-					},
+// # To
+//
+//	{
+//	  "anyOf": [
+//	    { "allOf": [ CONDITION, SCHEMA1 ] },
+//	    { "allOf": [ {"not": CONDITION }, SCHEMA2 ] }
+//	  ]
+//	}
+//
+// Without "else" it becomes "anyOf": [ {"not": CONDITION }, SCHEMA1 ], and without "then" - "anyOf": [ CONDITION, SCHEMA2 ].
+// When schema already has anyOf, both are kept and combined with allOf
+func materialImplication(schema map[string]interface{}) map[string]interface{} {
+	ifschema, ok := schema["if"].(map[string]interface{})
+	if !ok {
+		return schema
+	}
+	thenschema, hasThen := schema["then"].(map[string]interface{})
+	elseschema, hasElse := schema["else"].(map[string]interface{})
+	if !hasThen && !hasElse {
+		return schema
+	}
+	delete(schema, "if")
+	delete(schema, "then")
+	delete(schema, "else")
+	switch {
+	case !hasElse:
+		addAnyOf(schema, []interface{}{
+			map[string]interface{}{
+				"not": ifschema,
+			},
+			thenschema,
+		})
+	case !hasThen:
+		addAnyOf(schema, []interface{}{ifschema, elseschema})
+	default:
+		addAnyOf(schema, []interface{}{
+			map[string]interface{}{
+				"allOf": []interface{}{
+					ifschema, thenschema,
 				},
-				map[string][]interface{}{
-					"allOf": []interface{}{
-						map[string]interface{}{
-							"not": ifschema,
-						},
-						elseschema,
+			},
+			map[string]interface{}{
+				"allOf": []interface{}{
+					map[string]interface{}{
+						"not": ifschema,
 					},
+					elseschema,
 				},
-			}
-			delete(v, "if")
-			delete(v, "then")
-			delete(v, "else")
-		} else { // Go deeper
-			for k, subschema := range v {
-				v[k] = materialImplication(subschema)
-			}
-		}
-		return v
-	case []interface{}:
-		res := make([]interface{}, 0)
-		for _, elem := range v {
-			res = append(res, materialImplication(elem))
-		}
-		return res
-	default:
-		return v
+			},
+		})
 	}
+	return schema
 }
 
 func getCondition(jsonData interface{}) (ok bool, ifschema, thenschema, elseschema interface{}) {
@@ -195,27 +499,21 @@ func getCondition(jsonData interface{}) (ok bool, ifschema, thenschema, elsesche
 	if !ok {
 		return
 	}
-	ok = false
-	jq := jsonq.NewQuery(obj)
-	ifschema, err := jq.Object("if")
-	if err != nil {
-		return
-	}
-	thenschema, err = jq.Object("then")
-	if err != nil {
+	ifschema, ok = obj["if"].(map[string]interface{})
+	if !ok {
 		return
 	}
-	elseschema, err = jq.Object("else")
-	if err != nil {
+	thenschema, ok = obj["then"].(map[string]interface{})
+	if !ok {
 		return
 	}
-	ok = true
+	elseschema, ok = obj["else"].(map[string]interface{})
 	return
 }
 
 // ["a", "b"], ["A", "B"] => {"a": "A", "b":"B"}
-func cases2refmapping(cases, refs []string) map[string]string {
-	res := make(map[string]string)
+func cases2refmapping(cases, refs []string) map[string]interface{} {
+	res := make(map[string]interface{})
 	for i, ref := range refs {
 		res[cases[i]] = ref
 	}
@@ -223,10 +521,10 @@ func cases2refmapping(cases, refs []string) map[string]string {
 }
 
 // ["a", "b"] => [{"$ref": "a"}, {"$ref": "b"}]
-func reflist(refs []string) []map[string]string {
-	res := make([]map[string]string, len(refs))
+func reflist(refs []string) []interface{} {
+	res := make([]interface{}, len(refs))
 	for i, r := range refs {
-		res[i] = map[string]string{
+		res[i] = map[string]interface{}{
 			"$ref": r,
 		}
 	}
@@ -247,12 +545,8 @@ func getCases(jsonData interface{}) (ok bool, res casesResult) {
 	if !ok {
 		return
 	}
-	jq := jsonq.NewQuery(obj)
-	oneOf, err := jq.Array("oneOf")
-	if err != nil {
-		return false, res
-	}
-	if len(oneOf) < 1 {
+	oneOf, ok := obj["oneOf"].([]interface{})
+	if !ok || len(oneOf) < 1 {
 		return false, res
 	}
 	for _, caseIface := range oneOf {
@@ -298,12 +592,8 @@ func getRef(jsonData interface{}) (ok bool, ref string) {
 	if !ok {
 		return false, ""
 	}
-	jq := jsonq.NewQuery(obj)
-	ref, err := jq.String("$ref")
-	if err != nil {
-		return false, ""
-	}
-	return true, ref
+	ref, ok = obj["$ref"].(string)
+	return ok, ref
 }
 
 // getConstant checks if JSON matches pattern { "properties": { "PROPERTY": { "enum": [ "CASE1" ] } } }
@@ -313,9 +603,8 @@ func getConstant(jsonData interface{}) (ok bool, name string, value string) {
 	if !ok {
 		return false, "", ""
 	}
-	jq := jsonq.NewQuery(obj)
-	properties, err := jq.Object("properties")
-	if err != nil {
+	properties, ok := obj["properties"].(map[string]interface{})
+	if !ok {
 		return false, "", ""
 	}
 	for k, v := range properties {
@@ -343,31 +632,15 @@ func getConstant(jsonData interface{}) (ok bool, name string, value string) {
 	return true, name, value
 }
 
-// Recursively replace "oneOf": [{"type": X}, {"type": "null"}]
+// replaceNullable replaces "oneOf": [{"type": X}, {"type": "null"}]
 // with "type": X, "nullable": true
-func replaceNullable(jsonData interface{}) interface{} {
-	switch jsonData.(type) {
-	case map[string]interface{}:
-		res := make(map[string]interface{})
-		for k, v := range jsonData.(map[string]interface{}) {
-			nullableType := isNullable(v)
-			if k == "oneOf" && nullableType != "" {
-				res["type"] = nullableType
-				res["nullable"] = true
-			} else {
-				res[k] = replaceNullable(v)
-			}
-		}
-		return res
-	case []interface{}:
-		res := make([]interface{}, 0)
-		for _, v := range jsonData.([]interface{}) {
-			res = append(res, replaceNullable(v))
-		}
-		return res
-	default:
+func replaceNullable(schema map[string]interface{}) map[string]interface{} {
+	if nullableType := isNullable(schema["oneOf"]); nullableType != "" {
+		delete(schema, "oneOf")
+		schema["type"] = nullableType
+		schema["nullable"] = true
 	}
-	return jsonData
+	return schema
 }
 
 // Check if json is of the form [{"type": X}, {"type": "null"}]
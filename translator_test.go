@@ -221,6 +221,31 @@ func ExamplePutSchemaIntoOpenAPI() {
 	fmt.Println(fullAPI)
 	// Output:
 	// {
+	//  "openapi": "3.0.0",
+	//  "info": {
+	//   "version": "1.0.0",
+	//   "title": "My API"
+	//  },
+	//  "servers": [],
+	//  "paths": {
+	//   "/data": {
+	//    "get": {
+	//     "summary": "Get data",
+	//     "responses": {
+	//      "200": {
+	//       "description": "Data response",
+	//       "content": {
+	//        "application/json": {
+	//         "schema": {
+	//          "$ref": "#/components/schemas/Data"
+	//         }
+	//        }
+	//       }
+	//      }
+	//     }
+	//    }
+	//   }
+	//  },
 	//  "components": {
 	//   "schemas": {
 	//    "Data": {
@@ -331,31 +356,6 @@ func ExamplePutSchemaIntoOpenAPI() {
 	//     "type": "int"
 	//    }
 	//   }
-	//  },
-	//  "info": {
-	//   "title": "My API",
-	//   "version": "1.0.0"
-	//  },
-	//  "openapi": "3.0.0",
-	//  "paths": {
-	//   "/data": {
-	//    "get": {
-	//     "responses": {
-	//      "200": {
-	//       "content": {
-	//        "application/json": {
-	//         "schema": {
-	//          "$ref": "#/components/schemas/Data"
-	//         }
-	//        }
-	//       },
-	//       "description": "Data response"
-	//      }
-	//     },
-	//     "summary": "Get data"
-	//    }
-	//   }
-	//  },
-	//  "servers": []
+	//  }
 	// }
 }
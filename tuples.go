@@ -0,0 +1,119 @@
+package jsonschema2openapi
+
+import "reflect"
+
+// tuples replaces tuple validation "items": [A, B, C] with "items": {"oneOf": [A, B, C]}, or anyOf
+// when the branches could overlap. Schema of "additionalItems" joins the branches, and when it is false,
+// "maxItems" is set to length of tuple. Unless "minItems" is given, it is set to length of tuple too,
+// so every item of tuple is there. Original items and additionalItems are kept in "x-tuple".
+func (t *translation) tuples(pointer string, schema map[string]interface{}) map[string]interface{} {
+	additional, hasAdditional := schema["additionalItems"]
+	delete(schema, "additionalItems")
+	items, ok := schema["items"].([]interface{})
+	if !ok {
+		return schema
+	}
+
+	tuple := map[string]interface{}{
+		"items": items,
+	}
+	branches := make([]interface{}, 0, len(items)+1)
+	for _, item := range items {
+		branches = appendUnique(branches, item)
+	}
+	switch a := additional.(type) {
+	case bool:
+		tuple["additionalItems"] = a
+		if a {
+			t.report(pointer, "items after tuple of %d are allowed to match only its schemas", len(items))
+		} else {
+			setMaxItems(schema, len(items))
+		}
+	case map[string]interface{}:
+		tuple["additionalItems"] = a
+		branches = appendUnique(branches, a)
+	default:
+		if !hasAdditional {
+			t.report(pointer, "items after tuple of %d are allowed to match only its schemas", len(items))
+		}
+	}
+	if _, ok := schema["minItems"]; !ok && len(items) > 0 {
+		schema["minItems"] = float64(len(items))
+		t.report(pointer, "arrays shorter than tuple of %d are not allowed", len(items))
+	}
+	schema["x-tuple"] = tuple
+	t.report(pointer, "positions of tuple items are not checked")
+
+	switch {
+	case len(branches) == 0:
+		schema["items"] = map[string]interface{}{}
+	case len(branches) == 1:
+		schema["items"] = branches[0]
+	case disjoint(branches):
+		schema["items"] = t.finish(pointer+"/items", map[string]interface{}{"oneOf": branches})
+	default:
+		schema["items"] = t.finish(pointer+"/items", map[string]interface{}{"anyOf": branches})
+	}
+	return schema
+}
+
+func appendUnique(schemas []interface{}, schema interface{}) []interface{} {
+	for _, s := range schemas {
+		if reflect.DeepEqual(s, schema) {
+			return schemas
+		}
+	}
+	return append(schemas, schema)
+}
+
+func setMaxItems(schema map[string]interface{}, max int) {
+	if current, ok := toFloat(schema["maxItems"]); ok && current <= float64(max) {
+		return
+	}
+	schema["maxItems"] = float64(max)
+}
+
+// disjoint checks if no value could match more than one of schemas, by looking at their types.
+// Null matches every schema which is nullable or has null in enum, so only one of them could be such.
+func disjoint(schemas []interface{}) bool {
+	types := make(map[string]bool)
+	nullable := false
+	for _, s := range schemas {
+		schema, ok := s.(map[string]interface{})
+		if !ok {
+			return false
+		}
+		if allowsNull(schema) {
+			if nullable {
+				return false
+			}
+			nullable = true
+		}
+		typ, ok := schema["type"].(string)
+		if !ok {
+			return false
+		}
+		if typ == "integer" {
+			typ = "number" // integers are numbers too
+		}
+		if types[typ] {
+			return false
+		}
+		types[typ] = true
+	}
+	return true
+}
+
+// allowsNull checks if schema is nullable or has null in enum
+func allowsNull(schema map[string]interface{}) bool {
+	if nullable, _ := schema["nullable"].(bool); nullable {
+		return true
+	}
+	enum, _ := schema["enum"].([]interface{})
+	for _, v := range enum {
+		if v == nil {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,118 @@
+package jsonschema2openapi
+
+import (
+	"encoding/json"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Tuples", func() {
+	It("should become oneOf of disjoint items", func() {
+		var definitions map[string]interface{}
+		Expect(json.Unmarshal([]byte(`{
+			"Point": {
+				"type": "array",
+				"items": [ { "type": "string" }, { "type": "number" } ],
+				"additionalItems": false
+			}
+		}`), &definitions)).To(Succeed())
+
+		res, err := json.Marshal(TranslateDefinitions(definitions))
+		Expect(err).To(BeNil())
+		Expect(res).To(MatchJSON(`{
+			"Point": {
+				"type": "array",
+				"items": { "oneOf": [ { "type": "string" }, { "type": "number" } ] },
+				"minItems": 2,
+				"maxItems": 2,
+				"x-tuple": {
+					"items": [ { "type": "string" }, { "type": "number" } ],
+					"additionalItems": false
+				}
+			}
+		}`))
+	})
+
+	It("should become anyOf of overlapping items", func() {
+		var definitions map[string]interface{}
+		Expect(json.Unmarshal([]byte(`{
+			"Range": {
+				"items": [ { "type": "number" }, { "type": "integer" }, { "type": "number" } ],
+				"additionalItems": { "type": "string" }
+			}
+		}`), &definitions)).To(Succeed())
+
+		res, err := json.Marshal(TranslateDefinitions(definitions))
+		Expect(err).To(BeNil())
+		Expect(res).To(MatchJSON(`{
+			"Range": {
+				"items": { "anyOf": [ { "type": "number" }, { "type": "integer" }, { "type": "string" } ] },
+				"minItems": 3,
+				"x-tuple": {
+					"items": [ { "type": "number" }, { "type": "integer" }, { "type": "number" } ],
+					"additionalItems": { "type": "string" }
+				}
+			}
+		}`))
+	})
+
+	It("should become anyOf when several items allow null", func() {
+		var definitions map[string]interface{}
+		Expect(json.Unmarshal([]byte(`{
+			"Pair": {
+				"items": [
+					{ "oneOf": [ { "type": "string" }, { "type": "null" } ] },
+					{ "type": "integer", "enum": [ 1, null ] }
+				],
+				"additionalItems": false
+			}
+		}`), &definitions)).To(Succeed())
+
+		res, err := json.Marshal(TranslateDefinitions(definitions))
+		Expect(err).To(BeNil())
+		Expect(res).To(MatchJSON(`{
+			"Pair": {
+				"items": { "anyOf": [
+					{ "type": "string", "nullable": true },
+					{ "type": "integer", "enum": [ 1, null ] }
+				] },
+				"minItems": 2,
+				"maxItems": 2,
+				"x-tuple": {
+					"items": [
+						{ "type": "string", "nullable": true },
+						{ "type": "integer", "enum": [ 1, null ] }
+					],
+					"additionalItems": false
+				}
+			}
+		}`))
+	})
+
+	It("should keep given minItems", func() {
+		for _, min := range []float64{0, 1, 3} {
+			res := TranslateDefinitions(map[string]interface{}{
+				"Pair": map[string]interface{}{
+					"items":    []interface{}{map[string]interface{}{"type": "string"}, map[string]interface{}{"type": "string"}},
+					"minItems": min,
+				},
+			})
+			Expect(res).To(HaveKeyWithValue("Pair", HaveKeyWithValue("minItems", min)))
+		}
+	})
+
+	It("should drop additionalItems of list", func() {
+		res := TranslateDefinitions(map[string]interface{}{
+			"List": map[string]interface{}{
+				"items":           map[string]interface{}{"type": "string"},
+				"additionalItems": false,
+			},
+		})
+		Expect(res).To(Equal(map[string]interface{}{
+			"List": map[string]interface{}{
+				"items": map[string]interface{}{"type": "string"},
+			},
+		}))
+	})
+})
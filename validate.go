@@ -0,0 +1,50 @@
+package jsonschema2openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/bunyk/jsonschema2openapi/validator"
+)
+
+var openAPIValidator = newOpenAPIValidator()
+
+func newOpenAPIValidator() *validator.Validator {
+	var schema interface{}
+	err := json.Unmarshal([]byte(openAPISchemaJSON), &schema)
+	if err != nil {
+		panic(err)
+	}
+	return validator.New(schema, validator.Draft04)
+}
+
+// ValidationErrors lists every place where OpenAPI spec violates OpenAPI 3.0 schema
+type ValidationErrors []validator.Error
+
+func (errs ValidationErrors) Error() string {
+	messages := make([]string, len(errs))
+	for i, e := range errs {
+		messages[i] = e.Error()
+	}
+	return fmt.Sprintf("Error %s. OpenAPI spec is not valid", strings.Join(messages, "; "))
+}
+
+// Validate checks OpenAPI spec against OpenAPI 3.0 schema. If spec is not valid, ValidationErrors are returned,
+// with JSON pointer to every violation.
+func Validate(openAPISpec string) error {
+	var spec interface{}
+	err := decodeJSON([]byte(openAPISpec), &spec)
+	if err != nil {
+		return fmt.Errorf("Error %s. Not able to parse OpenAPI spec", err.Error())
+	}
+	return validateSpec(spec)
+}
+
+func validateSpec(spec interface{}) error {
+	errs := openAPIValidator.Validate(spec)
+	if len(errs) > 0 {
+		return ValidationErrors(errs)
+	}
+	return nil
+}
@@ -0,0 +1,48 @@
+package jsonschema2openapi
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var validOpenAPI = `{
+	"openapi": "3.0.0",
+	"info": { "title": "My API", "version": "1.0.0" },
+	"paths": {},
+	"components": { "schemas": {} }
+}`
+
+var _ = Describe("Validate", func() {
+	It("should accept valid spec", func() {
+		api, err := PutSchemaIntoOpenAPIWithOptions(`{
+			"definitions": {
+				"Name": { "oneOf": [ { "type": "string" }, { "type": "null" } ] }
+			}
+		}`, validOpenAPI, Options{Validate: true})
+		Expect(err).To(BeNil())
+		Expect(Validate(api)).To(Succeed())
+	})
+
+	It("should report every violation with its pointer", func() {
+		_, err := PutSchemaIntoOpenAPIWithOptions(`{
+			"definitions": {
+				"Name": { "type": ["string", "integer"] },
+				"Version": { "const": "v1" }
+			}
+		}`, validOpenAPI, Options{Validate: true})
+		Expect(err).To(HaveOccurred())
+
+		errs, ok := err.(ValidationErrors)
+		Expect(ok).To(BeTrue())
+		pointers := []string{}
+		for _, e := range errs {
+			pointers = append(pointers, e.Pointer)
+		}
+		Expect(pointers).To(ContainElement("/components/schemas/Name/type"))
+		Expect(pointers).To(ContainElement("/components/schemas/Version/const"))
+	})
+
+	It("should fail on spec which is not JSON", func() {
+		Expect(Validate("openapi: 3.0.0")).NotTo(Succeed())
+	})
+})
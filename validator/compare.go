@@ -0,0 +1,55 @@
+package validator
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// Mismatch is instance accepted by one schema and rejected by another
+type Mismatch struct {
+	Instance interface{}
+	// Errors are violations reported by schema which rejected instance
+	Errors []Error
+	// AcceptedBySource tells which of schemas accepted instance
+	AcceptedBySource bool
+}
+
+func (m Mismatch) String() string {
+	instance, _ := json.Marshal(m.Instance)
+	side := "translated"
+	if m.AcceptedBySource {
+		side = "source"
+	}
+	messages := make([]string, len(m.Errors))
+	for i, e := range m.Errors {
+		messages[i] = e.Error()
+	}
+	return string(instance) + " is accepted only by " + side + " schema: " + strings.Join(messages, "; ")
+}
+
+// Compare validates instances against schema referenced by sourceRef in source, and schema referenced
+// by targetRef in target, and returns every instance which only one of them accepts.
+// When instances is nil, they are generated from both schemas.
+func Compare(source *Validator, sourceRef string, target *Validator, targetRef string, instances []interface{}) []Mismatch {
+	if instances == nil {
+		instances = append(source.Instances(sourceRef), target.Instances(targetRef)...)
+	}
+	var res []Mismatch
+	seen := make(map[string]bool)
+	for _, instance := range instances {
+		key := compact(instance)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		sourceErrs := source.ValidateAt(sourceRef, instance)
+		targetErrs := target.ValidateAt(targetRef, instance)
+		switch {
+		case len(sourceErrs) == 0 && len(targetErrs) > 0:
+			res = append(res, Mismatch{Instance: instance, Errors: targetErrs, AcceptedBySource: true})
+		case len(sourceErrs) > 0 && len(targetErrs) == 0:
+			res = append(res, Mismatch{Instance: instance, Errors: sourceErrs})
+		}
+	}
+	return res
+}
@@ -0,0 +1,51 @@
+package validator
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Compare", func() {
+	It("should find instances accepted by only one schema", func() {
+		source := New(parse(`{"definitions": {"Age": {"type": "integer", "minimum": 0}}}`), Draft07)
+		target := New(parse(`{"components": {"schemas": {"Age": {"type": "number", "minimum": 0}}}}`), OpenAPI30)
+
+		mismatches := Compare(source, "#/definitions/Age", target, "#/components/schemas/Age", nil)
+		Expect(mismatches).NotTo(BeEmpty())
+		instances := []interface{}{}
+		for _, m := range mismatches {
+			Expect(m.AcceptedBySource).To(BeFalse())
+			instances = append(instances, m.Instance)
+		}
+		Expect(instances).To(ConsistOf(0.5, 1.5))
+	})
+
+	It("should find nothing for equivalent schemas", func() {
+		source := New(parse(`{"type": ["string", "null"], "exclusiveMaximum": 5}`), Draft07)
+		target := New(parse(`{"type": "string", "nullable": true, "maximum": 5, "exclusiveMaximum": true}`), OpenAPI30)
+		Expect(Compare(source, "#", target, "#", nil)).To(BeEmpty())
+	})
+
+	It("should use discriminator of OpenAPI schema", func() {
+		target := New(parse(`{"components": {"schemas": {
+			"Pet": {
+				"oneOf": [ { "$ref": "#/components/schemas/Cat" }, { "$ref": "#/components/schemas/Dog" } ],
+				"discriminator": { "propertyName": "kind", "mapping": { "cat": "#/components/schemas/Cat" } }
+			},
+			"Cat": { "type": "object", "properties": { "kind": { "type": "string" } } },
+			"Dog": { "type": "object", "properties": { "kind": { "type": "string" } } }
+		}}}`), OpenAPI30)
+		Expect(target.ValidateAt("#/components/schemas/Pet", parse(`{"kind": "cat"}`))).To(BeEmpty())
+		Expect(target.ValidateAt("#/components/schemas/Pet", parse(`{"kind": "Dog"}`))).To(BeEmpty())
+		Expect(target.ValidateAt("#/components/schemas/Pet", parse(`{"kind": "cow"}`))).To(HaveLen(1))
+	})
+})
+
+var _ = Describe("Instances", func() {
+	It("should include boundaries and enum members", func() {
+		v := New(parse(`{"properties": {"size": {"enum": ["S", "M"]}, "count": {"minimum": 10}}}`), Draft07)
+		instances := v.Instances("#")
+		Expect(instances).To(ContainElement(map[string]interface{}{"size": "M", "count": 10.0}))
+		Expect(instances).To(ContainElement(map[string]interface{}{"size": "S", "count": 9.0}))
+	})
+})
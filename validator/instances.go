@@ -0,0 +1,305 @@
+package validator
+
+import (
+	"sort"
+	"strings"
+)
+
+// Limits of instance generation
+const (
+	maxInstanceDepth = 3
+	maxInstances     = 400
+	// how many values of each property are tried
+	maxPropertyValues = 8
+)
+
+// Instances generates values for checking schema referenced by ref: values both accepted and rejected by it,
+// like boundaries of numbers and lengths, enum members, objects with and without required properties.
+// Generation is deterministic, so the same schema always gives the same instances.
+func (v *Validator) Instances(ref string) []interface{} {
+	schema, ok := v.resolve(ref)
+	if !ok {
+		return nil
+	}
+	g := generator{v: v, seen: make(map[string]bool)}
+	for _, sample := range basicSamples() {
+		g.add(sample)
+	}
+	g.generate(schema, 0)
+	return g.res
+}
+
+type generator struct {
+	v    *Validator
+	res  []interface{}
+	seen map[string]bool
+}
+
+// add appends instance to result, unless it was already added
+func (g *generator) add(instance interface{}) {
+	if len(g.res) >= maxInstances {
+		return
+	}
+	key := compact(instance)
+	if g.seen[key] {
+		return
+	}
+	g.seen[key] = true
+	g.res = append(g.res, instance)
+}
+
+func basicSamples() []interface{} {
+	return []interface{}{
+		nil, true, false,
+		0.0, 1.0, -1.0, 1.5,
+		"", "a", "abc",
+		[]interface{}{},
+		map[string]interface{}{},
+	}
+}
+
+// samples returns values generated for schema alone, without adding them to result
+func (g *generator) samples(schema interface{}, depth int) []interface{} {
+	sub := generator{v: g.v, seen: make(map[string]bool)}
+	sub.generate(schema, depth)
+	return sub.res
+}
+
+func (g *generator) generate(schema interface{}, depth int) {
+	s, ok := schema.(map[string]interface{})
+	if !ok || depth > maxInstanceDepth {
+		return
+	}
+	if ref, ok := s["$ref"].(string); ok {
+		if target, ok := g.v.resolve(ref); ok {
+			g.generate(target, depth+1)
+		}
+		return
+	}
+
+	for _, keyword := range []string{"enum", "examples"} {
+		if values, ok := s[keyword].([]interface{}); ok {
+			for _, value := range values {
+				g.add(value)
+			}
+		}
+	}
+	for _, keyword := range []string{"const", "default", "example"} {
+		if value, ok := s[keyword]; ok {
+			g.add(value)
+		}
+	}
+	for _, keyword := range []string{"minimum", "maximum", "exclusiveMinimum", "exclusiveMaximum", "multipleOf"} {
+		if n, ok := Number(s[keyword]); ok {
+			g.add(n - 1)
+			g.add(n)
+			g.add(n + 0.5)
+			g.add(n + 1)
+		}
+	}
+	for _, keyword := range []string{"minLength", "maxLength"} {
+		if n, ok := Number(s[keyword]); ok {
+			for _, length := range []float64{n - 1, n, n + 1} {
+				if length >= 0 {
+					g.add(strings.Repeat("a", int(length)))
+				}
+			}
+		}
+	}
+	if t, ok := s["type"].(string); ok && t == "array" || s["items"] != nil {
+		g.arrays(s, depth)
+	}
+	if s["properties"] != nil || s["required"] != nil || s["dependencies"] != nil || s["additionalProperties"] != nil {
+		g.objects(s, depth)
+	}
+
+	for _, keyword := range []string{"allOf", "anyOf", "oneOf"} {
+		if schemas, ok := s[keyword].([]interface{}); ok {
+			for _, sub := range schemas {
+				g.generate(sub, depth+1)
+			}
+		}
+	}
+	if keyword, ok := s["allOf"].([]interface{}); ok && len(keyword) > 1 {
+		g.merged(keyword, depth)
+	}
+	for _, keyword := range []string{"not", "if", "then", "else", "additionalProperties"} {
+		g.generate(s[keyword], depth+1)
+	}
+	if _, ok := s["if"]; ok {
+		g.merged([]interface{}{s["if"], s["then"]}, depth)
+	}
+	if discriminator, ok := s["discriminator"].(map[string]interface{}); ok {
+		g.discriminated(discriminator, depth)
+	}
+}
+
+// discriminated adds objects generated from schemas in discriminator mapping, with discriminator property set
+func (g *generator) discriminated(discriminator map[string]interface{}, depth int) {
+	property, _ := discriminator["propertyName"].(string)
+	mapping, _ := discriminator["mapping"].(map[string]interface{})
+	for _, value := range sortedKeys(mapping) {
+		ref, _ := mapping[value].(string)
+		target, ok := g.v.resolve(ref)
+		if !ok {
+			continue
+		}
+		for _, sample := range g.samples(target, depth+1) {
+			if obj, ok := sample.(map[string]interface{}); ok {
+				g.add(with(obj, property, value))
+			}
+		}
+	}
+}
+
+func (g *generator) arrays(s map[string]interface{}, depth int) {
+	var items []interface{}
+	switch i := s["items"].(type) {
+	case []interface{}:
+		for _, schema := range i {
+			items = append(items, g.samples(schema, depth+1)...)
+		}
+	case nil:
+	default:
+		items = g.samples(i, depth+1)
+	}
+	if len(items) > maxPropertyValues {
+		items = items[:maxPropertyValues]
+	}
+	for _, item := range items {
+		g.add([]interface{}{item})
+	}
+	for i := 1; i < len(items); i++ {
+		g.add([]interface{}{items[i-1], items[i]})
+	}
+	if len(items) > 0 {
+		g.add([]interface{}{items[0], items[0]})
+		g.add(append(append([]interface{}{}, items...), items[0]))
+	}
+}
+
+func (g *generator) objects(s map[string]interface{}, depth int) {
+	properties, _ := s["properties"].(map[string]interface{})
+	names := sortedKeys(properties)
+	if required, ok := s["required"].([]interface{}); ok {
+		for _, r := range required {
+			if name, ok := r.(string); ok {
+				if _, ok := properties[name]; !ok {
+					names = append(names, name)
+				}
+			}
+		}
+	}
+	if dependencies, ok := s["dependencies"].(map[string]interface{}); ok {
+		for _, name := range sortedKeys(dependencies) {
+			names = append(names, name)
+			if required, ok := dependencies[name].([]interface{}); ok {
+				for _, r := range required {
+					if dep, ok := r.(string); ok {
+						names = append(names, dep)
+					}
+				}
+			}
+		}
+	}
+	names = unique(names)
+
+	values := make(map[string][]interface{})
+	filler := make(map[string]interface{})
+	for _, name := range names {
+		var schema interface{} = map[string]interface{}{}
+		if p, ok := properties[name]; ok {
+			schema = p
+		}
+		vs := g.samples(schema, depth+1)
+		if len(vs) > maxPropertyValues {
+			vs = vs[:maxPropertyValues]
+		}
+		values[name] = vs
+		filler[name] = "a"
+		for _, value := range vs {
+			if len(g.v.validate(schema, "#", value, "", 0)) == 0 {
+				filler[name] = value
+				break
+			}
+		}
+	}
+
+	full := make(map[string]interface{})
+	for _, name := range names {
+		full[name] = filler[name]
+	}
+	g.add(full)
+	g.add(map[string]interface{}{"unknown": "a"})
+	for _, name := range names {
+		g.add(map[string]interface{}{name: filler[name]})
+		g.add(without(full, name))
+		for _, value := range values[name] {
+			g.add(with(full, name, value))
+		}
+	}
+}
+
+// merged adds objects combined from samples of every schema in allOf
+func (g *generator) merged(schemas []interface{}, depth int) {
+	combined := []map[string]interface{}{{}}
+	for _, schema := range schemas {
+		var next []map[string]interface{}
+		for _, sample := range g.samples(schema, depth+1) {
+			obj, ok := sample.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			for _, c := range combined {
+				if len(next) >= maxPropertyValues {
+					break
+				}
+				merged := copyObject(c)
+				for k, v := range obj {
+					merged[k] = v
+				}
+				next = append(next, merged)
+			}
+		}
+		if len(next) == 0 {
+			return
+		}
+		combined = next
+	}
+	for _, c := range combined {
+		g.add(c)
+	}
+}
+
+func copyObject(obj map[string]interface{}) map[string]interface{} {
+	res := make(map[string]interface{}, len(obj))
+	for k, v := range obj {
+		res[k] = v
+	}
+	return res
+}
+
+func with(obj map[string]interface{}, name string, value interface{}) map[string]interface{} {
+	res := copyObject(obj)
+	res[name] = value
+	return res
+}
+
+func without(obj map[string]interface{}, name string) map[string]interface{} {
+	res := copyObject(obj)
+	delete(res, name)
+	return res
+}
+
+func unique(names []string) []string {
+	seen := make(map[string]bool)
+	res := make([]string, 0, len(names))
+	for _, name := range names {
+		if !seen[name] {
+			seen[name] = true
+			res = append(res, name)
+		}
+	}
+	sort.Strings(res)
+	return res
+}
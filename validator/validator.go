@@ -0,0 +1,641 @@
+// Package validator checks JSON instances against JSON Schema draft-04 and draft-07 schemas,
+// and against OpenAPI 3.0 Schema Objects.
+//
+// Instances and schemas are values produced by encoding/json: maps, slices, strings, numbers, booleans and nil.
+// Only local references like "#/definitions/Name" are resolved, and "format" is not checked.
+package validator
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// Dialect is specification used to interpret schema keywords
+type Dialect int
+
+const (
+	// Draft04 is JSON Schema draft-04, used by OpenAPI 3.0 specification schema
+	Draft04 Dialect = iota
+	// Draft07 is JSON Schema draft-07
+	Draft07
+	// OpenAPI30 is OpenAPI 3.0 Schema Object
+	OpenAPI30
+)
+
+// Implicit target of discriminator value which is not in mapping
+const componentsPrefix = "#/components/schemas/"
+
+// Maximal depth of references followed without going deeper into instance
+const maxRefDepth = 64
+
+// Error describes place where instance violates schema
+type Error struct {
+	// Pointer is JSON pointer to invalid value in instance, like "/users/0/name"
+	Pointer string
+	// SchemaPointer is reference to keyword in schema which is violated, like "#/properties/name/type"
+	SchemaPointer string
+	Message       string
+}
+
+func (e Error) Error() string {
+	pointer := e.Pointer
+	if pointer == "" {
+		pointer = "/"
+	}
+	return pointer + ": " + e.Message
+}
+
+// Validator checks instances against schema. It is safe for concurrent use.
+type Validator struct {
+	root    interface{}
+	dialect Dialect
+
+	mu       sync.Mutex
+	patterns map[string]*regexp.Regexp
+}
+
+// New returns Validator for schema. References in schema are resolved relative to schema itself.
+func New(schema interface{}, dialect Dialect) *Validator {
+	return &Validator{
+		root:     schema,
+		dialect:  dialect,
+		patterns: make(map[string]*regexp.Regexp),
+	}
+}
+
+// Validate returns every violation of schema by instance, or nothing if instance is valid
+func (v *Validator) Validate(instance interface{}) []Error {
+	return v.validate(v.root, "#", instance, "", 0)
+}
+
+// ValidateAt validates instance against subschema of validator schema, referenced like "#/definitions/Name"
+func (v *Validator) ValidateAt(ref string, instance interface{}) []Error {
+	schema, ok := v.resolve(ref)
+	if !ok {
+		return []Error{{SchemaPointer: ref, Message: fmt.Sprintf("reference %s could not be resolved", ref)}}
+	}
+	return v.validate(schema, ref, instance, "", 0)
+}
+
+// Valid checks if instance is valid against schema
+func (v *Validator) Valid(instance interface{}) bool {
+	return len(v.Validate(instance)) == 0
+}
+
+func (v *Validator) validate(schema interface{}, sp string, instance interface{}, ip string, depth int) []Error {
+	switch s := schema.(type) {
+	case bool:
+		if s || v.dialect != Draft07 {
+			return nil
+		}
+		return []Error{{ip, sp, "no value is allowed"}}
+	case map[string]interface{}:
+		return v.validateObject(s, sp, instance, ip, depth)
+	default:
+		return nil
+	}
+}
+
+func (v *Validator) validateObject(s map[string]interface{}, sp string, instance interface{}, ip string, depth int) []Error {
+	if ref, ok := s["$ref"].(string); ok {
+		if depth > maxRefDepth {
+			return []Error{{ip, sp + "/$ref", "too deep recursion of references"}}
+		}
+		target, ok := v.resolve(ref)
+		if !ok {
+			return []Error{{ip, sp + "/$ref", fmt.Sprintf("reference %s could not be resolved", ref)}}
+		}
+		return v.validate(target, ref, instance, ip, depth+1)
+	}
+
+	var errs []Error
+	fail := func(keyword, format string, args ...interface{}) {
+		errs = append(errs, Error{ip, sp + "/" + keyword, fmt.Sprintf(format, args...)})
+	}
+
+	if t, ok := s["type"]; ok && !v.hasType(s, t, instance) {
+		fail("type", "should be %s, but is %s", typeNames(t), typeOf(instance))
+		return errs // other keywords are not going to make sense
+	}
+	if instance == nil && v.dialect == OpenAPI30 && s["nullable"] == true {
+		return errs
+	}
+	if enum, ok := s["enum"].([]interface{}); ok && !contains(enum, instance) {
+		fail("enum", "should be one of %s", compact(enum))
+	}
+	if c, ok := s["const"]; ok && v.dialect == Draft07 && !EqualJSON(c, instance) {
+		fail("const", "should be %s", compact(c))
+	}
+
+	switch inst := instance.(type) {
+	case string:
+		errs = append(errs, v.validateString(s, sp, inst, ip)...)
+	case []interface{}:
+		errs = append(errs, v.validateArray(s, sp, inst, ip, depth)...)
+	case map[string]interface{}:
+		errs = append(errs, v.validateProperties(s, sp, inst, ip, depth)...)
+	default:
+		if n, ok := Number(instance); ok {
+			errs = append(errs, v.validateNumber(s, sp, n, ip)...)
+		}
+	}
+
+	if allOf, ok := s["allOf"].([]interface{}); ok {
+		for i, sub := range allOf {
+			errs = append(errs, v.validate(sub, sp+"/allOf/"+strconv.Itoa(i), instance, ip, depth)...)
+		}
+	}
+	if anyOf, ok := s["anyOf"].([]interface{}); ok {
+		if valid, branchErrs := v.branches(anyOf, sp+"/anyOf", instance, ip, depth); valid == 0 {
+			errs = append(errs, branchErrs...)
+		}
+	}
+	if discriminator, ok := s["discriminator"].(map[string]interface{}); ok && v.dialect == OpenAPI30 {
+		if obj, ok := instance.(map[string]interface{}); ok {
+			return append(errs, v.discriminate(discriminator, sp+"/discriminator", obj, ip, depth)...)
+		}
+	}
+	if oneOf, ok := s["oneOf"].([]interface{}); ok {
+		valid, branchErrs := v.branches(oneOf, sp+"/oneOf", instance, ip, depth)
+		if valid == 0 {
+			errs = append(errs, branchErrs...)
+		} else if valid > 1 {
+			fail("oneOf", "should match exactly one schema, but matches %d", valid)
+		}
+	}
+	if not, ok := s["not"]; ok && len(v.validate(not, sp+"/not", instance, ip, depth)) == 0 {
+		fail("not", "should not match schema")
+	}
+	if cond, ok := s["if"]; ok && v.dialect == Draft07 {
+		if len(v.validate(cond, sp+"/if", instance, ip, depth)) == 0 {
+			if then, ok := s["then"]; ok {
+				errs = append(errs, v.validate(then, sp+"/then", instance, ip, depth)...)
+			}
+		} else if els, ok := s["else"]; ok {
+			errs = append(errs, v.validate(els, sp+"/else", instance, ip, depth)...)
+		}
+	}
+	return errs
+}
+
+// discriminate validates object against schema chosen by value of discriminator property,
+// instead of trying every schema of oneOf or anyOf
+func (v *Validator) discriminate(discriminator map[string]interface{}, sp string, obj map[string]interface{}, ip string, depth int) []Error {
+	property, _ := discriminator["propertyName"].(string)
+	value, ok := obj[property].(string)
+	if !ok {
+		return []Error{{ip, sp + "/propertyName", fmt.Sprintf("property %q should be string used as discriminator", property)}}
+	}
+	ref := componentsPrefix + escape(value)
+	if mapping, ok := discriminator["mapping"].(map[string]interface{}); ok {
+		if mapped, ok := mapping[value].(string); ok {
+			ref = mapped
+		}
+	}
+	target, ok := v.resolve(ref)
+	if !ok {
+		return []Error{{ip + "/" + escape(property), sp + "/mapping", fmt.Sprintf("value %q is not mapped to schema", value)}}
+	}
+	if depth > maxRefDepth {
+		return []Error{{ip, sp, "too deep recursion of references"}}
+	}
+	return v.validate(target, ref, obj, ip, depth+1)
+}
+
+// branches validates instance against each of schemas, returning number of matching ones.
+// When none matches, errors of the branch which got furthest into instance are returned.
+func (v *Validator) branches(schemas []interface{}, sp string, instance interface{}, ip string, depth int) (int, []Error) {
+	valid := 0
+	var best []Error
+	bestDepth := -1
+	for i, sub := range schemas {
+		errs := v.validate(sub, sp+"/"+strconv.Itoa(i), instance, ip, depth)
+		if len(errs) == 0 {
+			valid++
+			continue
+		}
+		d := 0
+		for _, e := range errs {
+			if n := strings.Count(e.Pointer, "/"); n > d {
+				d = n
+			}
+		}
+		if d > bestDepth || (d == bestDepth && len(errs) < len(best)) {
+			best, bestDepth = errs, d
+		}
+	}
+	return valid, best
+}
+
+func (v *Validator) hasType(s map[string]interface{}, t interface{}, instance interface{}) bool {
+	if instance == nil && v.dialect == OpenAPI30 && s["nullable"] == true {
+		return true
+	}
+	switch typ := t.(type) {
+	case string:
+		return isType(instance, typ)
+	case []interface{}:
+		for _, elem := range typ {
+			if name, ok := elem.(string); ok && isType(instance, name) {
+				return true
+			}
+		}
+		return false
+	default:
+		return true
+	}
+}
+
+func (v *Validator) validateNumber(s map[string]interface{}, sp string, n float64, ip string) []Error {
+	var errs []Error
+	fail := func(keyword, format string, args ...interface{}) {
+		errs = append(errs, Error{ip, sp + "/" + keyword, fmt.Sprintf(format, args...)})
+	}
+	if m, ok := Number(s["multipleOf"]); ok && m > 0 {
+		if q := n / m; math.Abs(q-math.Round(q)) > 1e-9 {
+			fail("multipleOf", "should be multiple of %v", m)
+		}
+	}
+	if max, ok := Number(s["maximum"]); ok {
+		if s["exclusiveMaximum"] == true && v.dialect != Draft07 {
+			if n >= max {
+				fail("maximum", "should be less than %v", max)
+			}
+		} else if n > max {
+			fail("maximum", "should be at most %v", max)
+		}
+	}
+	if min, ok := Number(s["minimum"]); ok {
+		if s["exclusiveMinimum"] == true && v.dialect != Draft07 {
+			if n <= min {
+				fail("minimum", "should be greater than %v", min)
+			}
+		} else if n < min {
+			fail("minimum", "should be at least %v", min)
+		}
+	}
+	if v.dialect == Draft07 {
+		if max, ok := Number(s["exclusiveMaximum"]); ok && n >= max {
+			fail("exclusiveMaximum", "should be less than %v", max)
+		}
+		if min, ok := Number(s["exclusiveMinimum"]); ok && n <= min {
+			fail("exclusiveMinimum", "should be greater than %v", min)
+		}
+	}
+	return errs
+}
+
+func (v *Validator) validateString(s map[string]interface{}, sp string, str string, ip string) []Error {
+	var errs []Error
+	fail := func(keyword, format string, args ...interface{}) {
+		errs = append(errs, Error{ip, sp + "/" + keyword, fmt.Sprintf(format, args...)})
+	}
+	length := utf8.RuneCountInString(str)
+	if max, ok := Number(s["maxLength"]); ok && float64(length) > max {
+		fail("maxLength", "should be at most %v characters long", max)
+	}
+	if min, ok := Number(s["minLength"]); ok && float64(length) < min {
+		fail("minLength", "should be at least %v characters long", min)
+	}
+	if pattern, ok := s["pattern"].(string); ok {
+		re, err := v.regexp(pattern)
+		if err != nil {
+			fail("pattern", "pattern %s is not supported: %s", pattern, err.Error())
+		} else if !re.MatchString(str) {
+			fail("pattern", "should match pattern %s", pattern)
+		}
+	}
+	return errs
+}
+
+func (v *Validator) validateArray(s map[string]interface{}, sp string, arr []interface{}, ip string, depth int) []Error {
+	var errs []Error
+	fail := func(keyword, format string, args ...interface{}) {
+		errs = append(errs, Error{ip, sp + "/" + keyword, fmt.Sprintf(format, args...)})
+	}
+	if max, ok := Number(s["maxItems"]); ok && float64(len(arr)) > max {
+		fail("maxItems", "should have at most %v items", max)
+	}
+	if min, ok := Number(s["minItems"]); ok && float64(len(arr)) < min {
+		fail("minItems", "should have at least %v items", min)
+	}
+	if s["uniqueItems"] == true {
+	unique:
+		for i := range arr {
+			for j := 0; j < i; j++ {
+				if EqualJSON(arr[i], arr[j]) {
+					fail("uniqueItems", "items %d and %d should not be equal", j, i)
+					break unique
+				}
+			}
+		}
+	}
+	switch items := s["items"].(type) {
+	case []interface{}:
+		if v.dialect == OpenAPI30 {
+			break
+		}
+		for i, elem := range arr {
+			isp, schema := sp+"/items/"+strconv.Itoa(i), interface{}(nil)
+			if i < len(items) {
+				schema = items[i]
+			} else if additional, ok := s["additionalItems"]; ok {
+				isp, schema = sp+"/additionalItems", additional
+			}
+			if b, ok := schema.(bool); ok && !b {
+				errs = append(errs, Error{ip + "/" + strconv.Itoa(i), isp, "item is not allowed"})
+				continue
+			}
+			errs = append(errs, v.validate(schema, isp, elem, ip+"/"+strconv.Itoa(i), depth)...)
+		}
+	case nil:
+	default:
+		for i, elem := range arr {
+			errs = append(errs, v.validate(items, sp+"/items", elem, ip+"/"+strconv.Itoa(i), depth)...)
+		}
+	}
+	if c, ok := s["contains"]; ok && v.dialect == Draft07 {
+		found := false
+		for i, elem := range arr {
+			if len(v.validate(c, sp+"/contains", elem, ip+"/"+strconv.Itoa(i), depth)) == 0 {
+				found = true
+				break
+			}
+		}
+		if !found {
+			fail("contains", "should contain matching item")
+		}
+	}
+	return errs
+}
+
+func (v *Validator) validateProperties(s map[string]interface{}, sp string, obj map[string]interface{}, ip string, depth int) []Error {
+	var errs []Error
+	fail := func(keyword, format string, args ...interface{}) {
+		errs = append(errs, Error{ip, sp + "/" + keyword, fmt.Sprintf(format, args...)})
+	}
+	if max, ok := Number(s["maxProperties"]); ok && float64(len(obj)) > max {
+		fail("maxProperties", "should have at most %v properties", max)
+	}
+	if min, ok := Number(s["minProperties"]); ok && float64(len(obj)) < min {
+		fail("minProperties", "should have at least %v properties", min)
+	}
+	if required, ok := s["required"].([]interface{}); ok {
+		for _, r := range required {
+			if name, ok := r.(string); ok {
+				if _, ok := obj[name]; !ok {
+					fail("required", "property %q is required", name)
+				}
+			}
+		}
+	}
+
+	properties, _ := s["properties"].(map[string]interface{})
+	patternProperties, _ := s["patternProperties"].(map[string]interface{})
+	if v.dialect == OpenAPI30 {
+		patternProperties = nil
+	}
+	patterns := sortedKeys(patternProperties)
+	for _, name := range sortedKeys(obj) {
+		value, pointer := obj[name], ip+"/"+escape(name)
+		matched := false
+		if schema, ok := properties[name]; ok {
+			matched = true
+			errs = append(errs, v.validate(schema, sp+"/properties/"+escape(name), value, pointer, depth)...)
+		}
+		for _, pattern := range patterns {
+			re, err := v.regexp(pattern)
+			if err != nil || !re.MatchString(name) {
+				continue
+			}
+			matched = true
+			errs = append(errs, v.validate(patternProperties[pattern], sp+"/patternProperties/"+escape(pattern), value, pointer, depth)...)
+		}
+		if matched {
+			continue
+		}
+		switch additional := s["additionalProperties"].(type) {
+		case bool:
+			if !additional {
+				errs = append(errs, Error{pointer, sp + "/additionalProperties", fmt.Sprintf("property %q is not allowed", name)})
+			}
+		case map[string]interface{}:
+			errs = append(errs, v.validate(additional, sp+"/additionalProperties", value, pointer, depth)...)
+		}
+	}
+	if names, ok := s["propertyNames"]; ok && v.dialect == Draft07 {
+		for _, name := range sortedKeys(obj) {
+			if len(v.validate(names, sp+"/propertyNames", name, ip+"/"+escape(name), depth)) > 0 {
+				fail("propertyNames", "property name %q is not allowed", name)
+			}
+		}
+	}
+
+	if dependencies, ok := s["dependencies"].(map[string]interface{}); ok && v.dialect != OpenAPI30 {
+		for _, name := range sortedKeys(dependencies) {
+			if _, ok := obj[name]; !ok {
+				continue
+			}
+			if required, ok := dependencies[name].([]interface{}); ok {
+				for _, r := range required {
+					if dep, ok := r.(string); ok {
+						if _, ok := obj[dep]; !ok {
+							fail("dependencies", "property %q is required by %q", dep, name)
+						}
+					}
+				}
+			} else {
+				errs = append(errs, v.validate(dependencies[name], sp+"/dependencies/"+escape(name), obj, ip, depth)...)
+			}
+		}
+	}
+	return errs
+}
+
+// resolve returns schema referenced by local reference
+func (v *Validator) resolve(ref string) (interface{}, bool) {
+	if !strings.HasPrefix(ref, "#") {
+		return nil, false
+	}
+	pointer, err := url.PathUnescape(ref[1:])
+	if err != nil {
+		pointer = ref[1:]
+	}
+	return Resolve(v.root, pointer)
+}
+
+func (v *Validator) regexp(pattern string) (*regexp.Regexp, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if re, ok := v.patterns[pattern]; ok {
+		return re, nil
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+	v.patterns[pattern] = re
+	return re, nil
+}
+
+// Resolve returns value at JSON pointer like "/definitions/a~1b" inside of document
+func Resolve(document interface{}, pointer string) (interface{}, bool) {
+	if pointer == "" {
+		return document, true
+	}
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, false
+	}
+	current := document
+	for _, token := range strings.Split(pointer[1:], "/") {
+		token = strings.Replace(strings.Replace(token, "~1", "/", -1), "~0", "~", -1)
+		switch c := current.(type) {
+		case map[string]interface{}:
+			next, ok := c[token]
+			if !ok {
+				return nil, false
+			}
+			current = next
+		case []interface{}:
+			i, err := strconv.Atoi(token)
+			if err != nil || i < 0 || i >= len(c) {
+				return nil, false
+			}
+			current = c[i]
+		default:
+			return nil, false
+		}
+	}
+	return current, true
+}
+
+// Number returns value of JSON number, decoded either as float64 or as json.Number
+func Number(jsonData interface{}) (float64, bool) {
+	switch n := jsonData.(type) {
+	case float64:
+		return n, true
+	case json.Number:
+		f, err := n.Float64()
+		return f, err == nil
+	case int:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	default:
+		return 0, false
+	}
+}
+
+// EqualJSON checks if JSON values are equal, treating numbers with the same value as equal
+func EqualJSON(a, b interface{}) bool {
+	if x, ok := Number(a); ok {
+		y, ok := Number(b)
+		return ok && x == y
+	}
+	switch x := a.(type) {
+	case []interface{}:
+		y, ok := b.([]interface{})
+		if !ok || len(x) != len(y) {
+			return false
+		}
+		for i := range x {
+			if !EqualJSON(x[i], y[i]) {
+				return false
+			}
+		}
+		return true
+	case map[string]interface{}:
+		y, ok := b.(map[string]interface{})
+		if !ok || len(x) != len(y) {
+			return false
+		}
+		for k, xv := range x {
+			yv, ok := y[k]
+			if !ok || !EqualJSON(xv, yv) {
+				return false
+			}
+		}
+		return true
+	default:
+		return a == b
+	}
+}
+
+func contains(values []interface{}, value interface{}) bool {
+	for _, v := range values {
+		if EqualJSON(v, value) {
+			return true
+		}
+	}
+	return false
+}
+
+func isType(instance interface{}, typ string) bool {
+	switch typ {
+	case "integer":
+		n, ok := Number(instance)
+		return ok && n == math.Trunc(n)
+	case "number":
+		_, ok := Number(instance)
+		return ok
+	default:
+		return typeOf(instance) == typ
+	}
+}
+
+func typeOf(instance interface{}) string {
+	switch instance.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case string:
+		return "string"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	default:
+		if _, ok := Number(instance); ok {
+			return "number"
+		}
+		return fmt.Sprintf("%T", instance)
+	}
+}
+
+func typeNames(t interface{}) string {
+	if types, ok := t.([]interface{}); ok {
+		names := make([]string, 0, len(types))
+		for _, name := range types {
+			names = append(names, fmt.Sprint(name))
+		}
+		return "one of " + strings.Join(names, ", ")
+	}
+	return fmt.Sprint(t)
+}
+
+func compact(jsonData interface{}) string {
+	res, _ := json.Marshal(jsonData)
+	return string(res)
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func escape(token string) string {
+	return strings.Replace(strings.Replace(token, "~", "~0", -1), "/", "~1", -1)
+}
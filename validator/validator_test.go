@@ -0,0 +1,85 @@
+package validator
+
+import (
+	"encoding/json"
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func parse(data string) interface{} {
+	var res interface{}
+	err := json.Unmarshal([]byte(data), &res)
+	if err != nil {
+		panic(err)
+	}
+	return res
+}
+
+func pointers(errs []Error) []string {
+	res := make([]string, len(errs))
+	for i, e := range errs {
+		res[i] = e.Pointer
+	}
+	return res
+}
+
+var _ = Describe("Draft-04", func() {
+	v := New(parse(`{
+		"type": "object",
+		"required": ["id"],
+		"properties": {
+			"id": { "type": "integer", "minimum": 0, "exclusiveMinimum": true },
+			"tags": { "type": "array", "items": { "$ref": "#/definitions/Tag" }, "uniqueItems": true },
+			"kind": { "enum": ["a", "b"] }
+		},
+		"additionalProperties": false,
+		"definitions": {
+			"Tag": { "type": "string", "pattern": "^[a-z]+$", "maxLength": 5 }
+		}
+	}`), Draft04)
+
+	It("should accept valid instance", func() {
+		Expect(v.Validate(parse(`{"id": 1, "tags": ["a", "b"], "kind": "a"}`))).To(BeEmpty())
+	})
+
+	It("should report every violation", func() {
+		errs := v.Validate(parse(`{"id": 0, "tags": ["a", "Bad", "toolong", "a"], "kind": "c", "extra": 1}`))
+		Expect(pointers(errs)).To(ConsistOf(
+			"/id", "/tags", "/tags/1", "/tags/2", "/kind", "/extra",
+		))
+	})
+
+	It("should report missing required property at its object", func() {
+		errs := v.Validate(parse(`{}`))
+		Expect(errs).To(HaveLen(1))
+		Expect(errs[0].SchemaPointer).To(Equal("#/required"))
+		Expect(errs[0].Error()).To(Equal(`/: property "id" is required`))
+	})
+})
+
+var _ = Describe("Combinators", func() {
+	v := New(parse(`{
+		"oneOf": [
+			{ "type": "object", "properties": { "a": { "type": "string" } }, "required": ["a"] },
+			{ "type": "number" }
+		],
+		"not": { "enum": [13] }
+	}`), Draft04)
+
+	It("should report errors of branch which got further", func() {
+		errs := v.Validate(parse(`{"a": 1}`))
+		Expect(pointers(errs)).To(Equal([]string{"/a"}))
+	})
+
+	It("should check not", func() {
+		Expect(v.Valid(parse(`13`))).To(BeFalse())
+		Expect(v.Valid(parse(`12`))).To(BeTrue())
+	})
+})
+
+func TestSuite(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Validator Suite")
+}
@@ -0,0 +1,237 @@
+package jsonschema2openapi
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Keywords which values are objects with schemas as values, so keys of those objects are names, not keywords
+var schemaMapKeywords = map[string]bool{
+	"properties":        true,
+	"patternProperties": true,
+	"definitions":       true,
+	"dependencies":      true,
+	"dependentSchemas":  true,
+}
+
+// Keywords which values are schemas
+var schemaKeywords = map[string]bool{
+	"items":                true,
+	"additionalItems":      true,
+	"additionalProperties": true,
+	"not":                  true,
+	"contains":             true,
+	"propertyNames":        true,
+	"if":                   true,
+	"then":                 true,
+	"else":                 true,
+}
+
+// Keywords which values are arrays of schemas
+var schemaArrayKeywords = map[string]bool{
+	"allOf": true,
+	"anyOf": true,
+	"oneOf": true,
+	"items": true,
+}
+
+// Keywords which values are instance data, or other objects which are not schemas.
+// "dependentRequired" maps properties to lists of property names.
+var dataKeywords = map[string]bool{
+	"dependentRequired": true,
+	"enum":              true,
+	"const":             true,
+	"default":           true,
+	"example":           true,
+	"examples":          true,
+	"discriminator":     true,
+	"xml":               true,
+	"externalDocs":      true,
+}
+
+// Extensions which translation puts into schemas to keep original keywords. Value of "x-tuple" is object
+// with "items" and "additionalItems" keywords of schema, value of "x-patternProperties" maps patterns to schemas.
+// References in them are followed like in the rest of schema.
+var schemaExtensions = map[string]bool{
+	"x-tuple": true,
+}
+
+var schemaMapExtensions = map[string]bool{
+	"x-patternProperties": true,
+}
+
+// hasSubschemas checks if value of keyword is schema, or array or object of them
+func hasSubschemas(keyword string) bool {
+	return schemaKeywords[keyword] || schemaArrayKeywords[keyword] || schemaMapKeywords[keyword]
+}
+
+// isExtension checks if key is OpenAPI specification extension, which value could be anything
+func isExtension(key string) bool {
+	return strings.HasPrefix(key, "x-")
+}
+
+// mapSchemas returns copy of jsonData where every schema object is replaced with result of f called on its copy.
+// Subschemas are replaced before schemas containing them. Values of data keywords and extensions are not touched,
+// and keys of properties, definitions and similar keywords are not mistaken for keywords.
+func mapSchemas(jsonData interface{}, f func(schema map[string]interface{}) map[string]interface{}) interface{} {
+	return mapSchemasAt(jsonData, "", func(_ string, schema map[string]interface{}) map[string]interface{} {
+		return f(schema)
+	})
+}
+
+// mapSchemaMap applies mapSchemas to every value of object like properties or definitions
+func mapSchemaMap(schemas map[string]interface{}, f func(schema map[string]interface{}) map[string]interface{}) map[string]interface{} {
+	return mapSchemaMapAt(schemas, "", func(_ string, schema map[string]interface{}) map[string]interface{} {
+		return f(schema)
+	})
+}
+
+// mapSchemasAt is mapSchemas which also passes to f JSON pointer of schema, relative to pointer of jsonData
+func mapSchemasAt(jsonData interface{}, pointer string, f func(pointer string, schema map[string]interface{}) map[string]interface{}) interface{} {
+	switch v := jsonData.(type) {
+	case map[string]interface{}:
+		res := make(map[string]interface{})
+		for k, value := range v {
+			schemas, ok := value.(map[string]interface{})
+			switch {
+			case dataKeywords[k] || isExtension(k):
+				res[k] = value
+			case schemaMapKeywords[k] && ok:
+				res[k] = mapSchemaMapAt(schemas, pointer+"/"+escapeRefToken(k), f)
+			default:
+				res[k] = mapSchemasAt(value, pointer+"/"+escapeRefToken(k), f)
+			}
+		}
+		return f(pointer, res)
+	case []interface{}:
+		res := make([]interface{}, 0, len(v))
+		for i, elem := range v {
+			res = append(res, mapSchemasAt(elem, pointer+"/"+strconv.Itoa(i), f))
+		}
+		return res
+	default:
+		return v
+	}
+}
+
+// mapSchemaMapAt is mapSchemaMap which also passes to f JSON pointer of schema, relative to pointer of schemas
+func mapSchemaMapAt(schemas map[string]interface{}, pointer string, f func(pointer string, schema map[string]interface{}) map[string]interface{}) map[string]interface{} {
+	res := make(map[string]interface{})
+	for k, v := range schemas {
+		if isPropertyList(v) {
+			res[k] = v
+			continue
+		}
+		res[k] = mapSchemasAt(v, pointer+"/"+escapeRefToken(k), f)
+	}
+	return res
+}
+
+// isPropertyList checks if value of "dependencies" is list of property names, and not schema
+func isPropertyList(value interface{}) bool {
+	_, ok := value.([]interface{})
+	return ok
+}
+
+// mapRefs returns copy of jsonData where every "$ref", and every reference of discriminator mapping in schemas,
+// is replaced with result of f. jsonData is walked like by collectRefs, so references in examples, defaults, enums
+// and extensions other than the ones made by translation are kept, and keys of properties are not mistaken for keywords. inSchema tells if jsonData
+// is schema, or other part of spec.
+func mapRefs(jsonData interface{}, inSchema bool, f func(ref string) string) interface{} {
+	return mapRefsAt(jsonData, "#", inSchema, f)
+}
+
+func mapRefsAt(jsonData interface{}, pointer string, inSchema bool, f func(ref string) string) interface{} {
+	switch v := jsonData.(type) {
+	case map[string]interface{}:
+		res := make(map[string]interface{}, len(v))
+		for k, value := range v {
+			p := pointer + "/" + escapeRefToken(k)
+			ref, isRef := value.(string)
+			discriminator, isDiscriminator := value.(map[string]interface{})
+			switch {
+			case k == "$ref" && isRef:
+				res[k] = f(ref)
+			case inSchema && schemaExtensions[k]:
+				res[k] = mapRefsAt(value, p, true, f)
+			case inSchema && schemaMapExtensions[k]:
+				res[k] = mapSchemaMapRefs(value, p, f)
+			case isExtension(k):
+				res[k] = value
+			case inSchema && schemaMapKeywords[k]:
+				res[k] = mapSchemaMapRefs(value, p, f)
+			case inSchema && k == "discriminator" && isDiscriminator:
+				res[k] = mapDiscriminatorRefs(discriminator, f)
+			case inSchema && dataKeywords[k]:
+				res[k] = value
+			case !inSchema && isExampleData(pointer, k):
+				res[k] = value
+			case !inSchema && pointer == "#/components" && k == "schemas":
+				res[k] = mapSchemaMapRefs(value, p, f)
+			case !inSchema && k == "schema":
+				res[k] = mapRefsAt(value, p, true, f)
+			default:
+				res[k] = mapRefsAt(value, p, inSchema, f)
+			}
+		}
+		return res
+	case []interface{}:
+		res := make([]interface{}, len(v))
+		for i, elem := range v {
+			res[i] = mapRefsAt(elem, pointer+"/"+strconv.Itoa(i), inSchema, f)
+		}
+		return res
+	default:
+		return v
+	}
+}
+
+// isExampleData checks if key of object at pointer, which is not schema, holds instance data:
+// "value" of Example Object, or "example" of media type, parameter or header
+func isExampleData(pointer, key string) bool {
+	tokens := strings.Split(pointer, "/")
+	if len(tokens) < 2 {
+		return false
+	}
+	parent := tokens[len(tokens)-2]
+	switch key {
+	case "value":
+		return parent == "examples"
+	case "example":
+		return parent == "content" || parent == "parameters" || parent == "headers"
+	}
+	return false
+}
+
+// mapSchemaMapRefs applies mapRefs to every schema of object like properties or components/schemas
+func mapSchemaMapRefs(jsonData interface{}, pointer string, f func(ref string) string) interface{} {
+	schemas, ok := jsonData.(map[string]interface{})
+	if !ok {
+		return jsonData
+	}
+	res := make(map[string]interface{}, len(schemas))
+	for name, schema := range schemas {
+		res[name] = mapRefsAt(schema, pointer+"/"+escapeRefToken(name), true, f)
+	}
+	return res
+}
+
+func mapDiscriminatorRefs(discriminator map[string]interface{}, f func(ref string) string) map[string]interface{} {
+	mapping, ok := discriminator["mapping"].(map[string]interface{})
+	if !ok {
+		return discriminator
+	}
+	res := make(map[string]interface{}, len(discriminator))
+	for k, v := range discriminator {
+		res[k] = v
+	}
+	resMapping := make(map[string]interface{}, len(mapping))
+	for k, v := range mapping {
+		if ref, ok := v.(string); ok {
+			v = f(ref)
+		}
+		resMapping[k] = v
+	}
+	res["mapping"] = resMapping
+	return res
+}